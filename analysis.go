@@ -0,0 +1,233 @@
+package schedule
+
+import (
+	"math/rand"
+	"time"
+)
+
+// SimulationConfig configures a Monte Carlo timing simulation. See Simulate.
+type SimulationConfig struct {
+	// Runs is the number of times the group is run. Must be greater than zero.
+	Runs int
+	// Period is the nominal interval between ScheduleNext calls of the simulated event loop.
+	Period time.Duration
+	// Jitter is the maximum random time added to each interval between calls.
+	Jitter time.Duration
+	// CallbackDelay is the maximum random time spent executing an action after
+	// it is scheduled, which delays the following ScheduleNext call.
+	CallbackDelay time.Duration
+	// Horizon limits the simulated time of each run. It is required
+	// for groups with infinite iterations.
+	Horizon time.Duration
+	// Seed seeds the random number generator so simulations are reproducible.
+	Seed int64
+}
+
+// SimulationResult summarizes a Monte Carlo timing simulation.
+type SimulationResult struct {
+	Runs int
+	// Failed is the number of runs in which the group returned an error.
+	Failed int
+	// MissProbability is the fraction of runs that failed.
+	MissProbability float64
+}
+
+// Simulate runs g many times in virtual time with randomized call jitter and
+// callback delays and reports how often the group failed, i.e. the probability
+// of missed actions for the event loop characteristics in cfg. This lets users
+// choose polling periods and tolerances with data before deploying.
+// g's state is reset by Begin on every run.
+func Simulate[T any](g Grouper[T], cfg SimulationConfig) (SimulationResult, error) {
+	switch {
+	case cfg.Runs <= 0:
+		return SimulationResult{}, errBadIterations
+	case cfg.Period <= 0:
+		return SimulationResult{}, errBadTickPeriod
+	case cfg.Jitter < 0 || cfg.CallbackDelay < 0 || cfg.Horizon < 0:
+		return SimulationResult{}, errNegativeDuration
+	case g.Iterations() == -1 && cfg.Horizon == 0:
+		return SimulationResult{}, errNoHorizon
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	randDuration := func(max time.Duration) time.Duration {
+		if max == 0 {
+			return 0
+		}
+		return time.Duration(rng.Int63n(int64(max) + 1))
+	}
+	start := tickEpoch
+	result := SimulationResult{Runs: cfg.Runs}
+	for run := 0; run < cfg.Runs; run++ {
+		g.Begin(start)
+		now := start.Add(randDuration(cfg.Jitter))
+		for cfg.Horizon == 0 || now.Sub(start) < cfg.Horizon {
+			_, ok, next, err := g.ScheduleNext(now)
+			if err != nil {
+				result.Failed++
+				break
+			}
+			if !ok && next == 0 {
+				break // Done.
+			}
+			if ok {
+				now = now.Add(randDuration(cfg.CallbackDelay))
+			}
+			now = now.Add(cfg.Period + randDuration(cfg.Jitter))
+		}
+	}
+	result.MissProbability = float64(result.Failed) / float64(result.Runs)
+	return result, nil
+}
+
+// LatencyReport is the result of a static latency analysis of a group. See AnalyzeLatency.
+type LatencyReport struct {
+	// WorstLateness is the worst-case time between an action being due and
+	// it being triggered when the group is polled at the analyzed period.
+	WorstLateness time.Duration
+	// Unserviceable holds the positions within an iteration of actions with non-zero
+	// duration and tolerance shorter than the polling period. Synchronized groups may
+	// miss them and fail while GroupLoose stretches them to the polling period.
+	Unserviceable []int
+}
+
+// latencyAnalyzer is implemented by groups that expose their timing to static analysis.
+type latencyAnalyzer interface {
+	analyzeLatency(pollPeriod time.Duration) LatencyReport
+}
+
+// maxLatencyIterations limits the iterations analyzed one by one. Groups running
+// more iterations are analyzed as if they ran forever, which may overestimate lateness.
+const maxLatencyIterations = 1 << 16
+
+// AnalyzeLatency computes the worst-case trigger lateness of each group when
+// polled every pollPeriod and flags actions too short for the polling period
+// to service, before anything runs. Reports are returned in the order of groups.
+// Polling is assumed to start at the time passed to Begin, as with a zero start
+// time. Groups whose start is not in phase with the polls, such as groups with
+// anchored actions or AlignTo set, and groups whose action durations vary at
+// runtime report a worst lateness of pollPeriod.
+// Groups that do not expose their actions are flagged as a whole: if their
+// required resolution is shorter than pollPeriod Unserviceable holds a single -1.
+func AnalyzeLatency(pollPeriod time.Duration, groups ...Resolver) []LatencyReport {
+	reports := make([]LatencyReport, len(groups))
+	for i, g := range groups {
+		if a, ok := g.(latencyAnalyzer); ok {
+			reports[i] = a.analyzeLatency(pollPeriod)
+			continue
+		}
+		reports[i].WorstLateness = pollPeriod
+		if res := g.RequiredResolution(); res > 0 && res < pollPeriod {
+			reports[i].Unserviceable = []int{-1}
+		}
+	}
+	return reports
+}
+
+func (g *GroupSync[T]) analyzeLatency(pollPeriod time.Duration) (report LatencyReport) {
+	for pos, a := range g.actions {
+		if a.Duration > 0 && a.Duration < pollPeriod && a.Tolerance < pollPeriod {
+			report.Unserviceable = append(report.Unserviceable, pos)
+		}
+	}
+	if g.alignTo > 0 || !g.fixedStart.IsZero() || hasAnchors(g.actions) {
+		report.WorstLateness = pollPeriod // Start is not in phase with polls.
+		return report
+	}
+	report.WorstLateness = syncLateness(g.offsets, g.startOffset, g.duration, g.iterations, pollPeriod)
+	return report
+}
+
+func (g *GroupLoose[T]) analyzeLatency(pollPeriod time.Duration) (report LatencyReport) {
+	report.Unserviceable = unserviceable(durationsOf(g.actions), pollPeriod)
+	if g.catchUp || g.jitter > 0 {
+		report.WorstLateness = pollPeriod // Durations vary at runtime.
+		return report
+	}
+	following := g.actions
+	if g.iterations == 1 {
+		following = following[:len(following)-1] // Last action is not followed by another.
+	}
+	// Actions start at a poll, so the following action is late by the time from
+	// the end of the action to the next poll.
+	for _, a := range following {
+		if late := (pollPeriod - a.Duration%pollPeriod) % pollPeriod; late > report.WorstLateness {
+			report.WorstLateness = late
+		}
+	}
+	return report
+}
+
+func (g *GroupNested[T]) analyzeLatency(pollPeriod time.Duration) (report LatencyReport) {
+	offsets, durations := spansOf(g.actionDurations())
+	report.Unserviceable = unserviceable(durations, pollPeriod)
+	report.WorstLateness = syncLateness(offsets, 0, g.duration, g.iterations, pollPeriod)
+	return report
+}
+
+// unserviceable returns the positions of non-zero durations shorter than pollPeriod.
+func unserviceable(durations []time.Duration, pollPeriod time.Duration) (positions []int) {
+	for pos, duration := range durations {
+		if duration > 0 && duration < pollPeriod {
+			positions = append(positions, pos)
+		}
+	}
+	return positions
+}
+
+// syncLateness returns the worst lateness of actions starting at offsets from
+// the start of each iteration of a group started delay after the first poll.
+// Each iteration shifts the actions relative to the polls by duration modulo
+// pollPeriod, so the shifts repeat after a cycle of iterations.
+func syncLateness(offsets []time.Duration, delay, duration time.Duration, iterations int, pollPeriod time.Duration) (worst time.Duration) {
+	shift := duration % pollPeriod
+	step := gcdDuration(shift, pollPeriod)
+	cycle := int(pollPeriod / step)
+	if iterations == -1 || iterations >= cycle || iterations > maxLatencyIterations {
+		// Over a whole cycle the shifts take every multiple of step.
+		// The earliest poll after a start not aligned to step is the latest.
+		for _, offset := range offsets {
+			late := pollPeriod - step
+			if rem := (delay + offset) % step; rem != 0 {
+				late = pollPeriod - rem
+			}
+			if late > worst {
+				worst = late
+			}
+		}
+		return worst
+	}
+	for _, offset := range offsets {
+		phase := (delay + offset) % pollPeriod
+		for i := 0; i < iterations; i++ {
+			if late := (pollPeriod - phase) % pollPeriod; late > worst {
+				worst = late
+			}
+			phase = (phase + shift) % pollPeriod
+		}
+	}
+	return worst
+}
+
+// gcdDuration returns the greatest common divisor of a and b.
+func gcdDuration(a, b time.Duration) time.Duration {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func (g *GroupNested[T]) actionDurations() []time.Duration {
+	durations := make([]time.Duration, g.actionsPerIter)
+	for pos := range durations {
+		durations[pos] = g.actionAt(pos).Duration
+	}
+	return durations
+}
+
+func durationsOf[T any](actions []Action[T]) []time.Duration {
+	durations := make([]time.Duration, len(actions))
+	for i := range actions {
+		durations[i] = actions[i].Duration
+	}
+	return durations
+}
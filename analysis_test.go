@@ -0,0 +1,93 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestSimulate(t *testing.T) {
+	actions := []actionInt{{Duration: 10 * time.Millisecond, Value: 1}, {Duration: 20 * time.Millisecond, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := schedule.SimulationConfig{Runs: 500, Period: 5 * time.Millisecond, Seed: 1}
+	result, err := schedule.Simulate[int](g, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed != 0 {
+		t.Errorf("got %d failed runs without jitter, want 0", result.Failed)
+	}
+
+	cfg.Jitter = 8 * time.Millisecond
+	cfg.CallbackDelay = 3 * time.Millisecond
+	result, err = schedule.Simulate[int](g, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MissProbability <= 0 || result.MissProbability >= 1 {
+		t.Errorf("got miss probability %g with jitter, want between 0 and 1", result.MissProbability)
+	}
+
+	gInf, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = schedule.Simulate[int](gInf, cfg); err == nil {
+		t.Error("expected error simulating infinite group without horizon")
+	}
+}
+
+func TestAnalyzeLatency(t *testing.T) {
+	actions := []actionInt{
+		{Duration: 10 * time.Millisecond, Value: 1},
+		{Duration: 2 * time.Millisecond, Value: 2},
+		{Duration: 0, Value: 3},
+		{Duration: 5 * time.Millisecond, Value: 4},
+	}
+	gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gl, err := schedule.NewGroupLoose(actions[:1], schedule.GroupLooseConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	infinite, err := schedule.NewGroupSync(actions[:1], schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	aligned, err := schedule.NewGroupSync(actions[:1], schedule.GroupSyncConfig{Iterations: 1, AlignTo: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tolerant, err := schedule.NewGroupSync([]actionInt{{Duration: 8 * time.Millisecond}, {Duration: time.Millisecond, Tolerance: 5 * time.Millisecond}}, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	const poll = 4 * time.Millisecond
+	reports := schedule.AnalyzeLatency(poll, gs, gl, infinite, aligned, tolerant)
+	if len(reports) != 5 {
+		t.Fatalf("got %d reports, want 5", len(reports))
+	}
+	// Actions are due at 0, 10 and 12ms in the synchronized group, at 10ms
+	// after the first action in the loose group and at every 10ms in the infinite group.
+	for i, want := range []time.Duration{2 * time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond, poll, 0} {
+		if got := reports[i].WorstLateness; got != want {
+			t.Errorf("report %d: got worst lateness %s, want %s", i, got, want)
+		}
+	}
+	if got := reports[4].Unserviceable; len(got) != 0 {
+		t.Errorf("got unserviceable %v, want none for action within tolerance", got)
+	}
+	if got := reports[0].Unserviceable; len(got) != 1 || got[0] != 1 {
+		t.Errorf("got unserviceable %v, want [1]", got)
+	}
+	if got := reports[1].Unserviceable; len(got) != 0 {
+		t.Errorf("got unserviceable %v, want none", got)
+	}
+}
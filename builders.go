@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+// NewRota returns one group per entity running the same repeating shift pattern,
+// such as 4 days on and 4 days off, with the phase of each entity offset from
+// the previous one by stagger. All groups should be started with the same call
+// to Begin so the offsets are preserved. Entity i starts i*stagger into the
+// pattern, splitting the action running at that point if needed.
+// Patterns with anchored actions are not supported.
+func NewRota[T any](pattern []Action[T], entities int, stagger time.Duration, cfg GroupSyncConfig) ([]*GroupSync[T], error) {
+	if entities <= 0 {
+		return nil, errBadIterations
+	}
+	if stagger < 0 {
+		return nil, errNegativeDuration
+	}
+	if hasAnchors(pattern) {
+		return nil, errAnchorIterations
+	}
+	duration, err := actionsDuration(pattern)
+	if err != nil && !errors.Is(err, ErrSmallDuration) {
+		return nil, err
+	}
+	if duration == 0 {
+		return nil, errZeroDuration
+	}
+	groups := make([]*GroupSync[T], entities)
+	var offset time.Duration
+	for i := range groups {
+		groups[i], err = NewGroupSync(rotateActions(pattern, offset), cfg)
+		if err != nil && !errors.Is(err, ErrSmallDuration) {
+			return nil, err
+		}
+		offset = (offset + stagger%duration) % duration
+	}
+	return groups, err // return ErrSmallDuration as a warning to users.
+}
+
+// rotateActions returns a copy of actions starting offset into them. The
+// action running at offset is split so the total duration is unchanged.
+func rotateActions[T any](actions []Action[T], offset time.Duration) []Action[T] {
+	rotated := make([]Action[T], 0, len(actions)+1)
+	var elapsed time.Duration
+	for i, a := range actions {
+		end := elapsed + a.Duration
+		if end < offset || (end == offset && a.Duration > 0) {
+			elapsed += a.Duration
+			continue
+		}
+		head := a
+		head.Duration = offset - elapsed
+		a.Duration -= head.Duration
+		rotated = append(rotated, a)
+		rotated = append(rotated, actions[i+1:]...)
+		rotated = append(rotated, actions[:i]...)
+		if head.Duration > 0 {
+			rotated = append(rotated, head)
+		}
+		return rotated
+	}
+	return append(rotated, actions...)
+}
+
+// IntervalPhase is the value scheduled by interval timers. See NewIntervalTimer.
+type IntervalPhase uint8
+
+const (
+	PhaseWork IntervalPhase = iota
+	PhaseShortBreak
+	PhaseLongBreak
+)
+
+func (p IntervalPhase) String() string {
+	switch p {
+	case PhaseWork:
+		return "work"
+	case PhaseShortBreak:
+		return "short break"
+	case PhaseLongBreak:
+		return "long break"
+	}
+	return "unknown phase"
+}
+
+// NewIntervalTimer returns a group alternating between work and short breaks with
+// a long break in place of every cycles-th short break, such as the Pomodoro
+// technique's 25 minutes of work, 5 minute breaks and a 15 minute break every
+// 4 cycles. Each iteration of the group is a full set of cycles ending in a long break.
+// The group is polled like any other GroupSync.
+func NewIntervalTimer(work, shortBreak, longBreak time.Duration, cycles int, cfg GroupSyncConfig) (*GroupSync[IntervalPhase], error) {
+	if cycles <= 0 {
+		return nil, errBadIterations
+	}
+	actions := make([]Action[IntervalPhase], 0, 2*cycles)
+	for i := 1; i < cycles; i++ {
+		actions = append(actions,
+			Action[IntervalPhase]{Duration: work, Value: PhaseWork},
+			Action[IntervalPhase]{Duration: shortBreak, Value: PhaseShortBreak},
+		)
+	}
+	actions = append(actions,
+		Action[IntervalPhase]{Duration: work, Value: PhaseWork},
+		Action[IntervalPhase]{Duration: longBreak, Value: PhaseLongBreak},
+	)
+	return NewGroupSync(actions, cfg)
+}
+
+// Every returns an infinite group that schedules value every period. If alignTo
+// is positive the group's start is aligned to multiples of alignTo, so a group
+// running every 5 minutes aligned to 5 minutes fires at :00, :05, :10 and so on
+// regardless of when Begin was called. See GroupSyncConfig.AlignTo.
+func Every[T any](period time.Duration, value T, alignTo time.Duration) (*GroupSync[T], error) {
+	return NewGroupSync([]Action[T]{{Duration: period, Value: value}}, GroupSyncConfig{
+		Iterations: -1,
+		AlignTo:    alignTo,
+	})
+}
+
+// AbsoluteAction is an action due at an absolute instant.
+type AbsoluteAction[T any] struct {
+	At    time.Time
+	Value T
+}
+
+// NewGroupAbsolute returns a single iteration group that schedules each action
+// at its absolute instant, for schedules computed from external sources such as
+// sunset times or flight plans. Instants must not decrease. Each action runs until
+// the next one is due. The last action lasts one nanosecond, so the group is done
+// right after it is scheduled, and is tolerated indefinitely, so it is delivered
+// however late the group is polled after it. The group's StartTime is the first instant
+// regardless of the time passed to Begin: a group started early idles until then
+// and a group started late handles overdue instants according to cfg.OnMiss.
+// cfg.Iterations and cfg.AlignTo are ignored.
+func NewGroupAbsolute[T any](actions []AbsoluteAction[T], cfg GroupSyncConfig) (*GroupSync[T], error) {
+	if len(actions) == 0 {
+		return nil, errEmptyActions
+	}
+	sync := make([]Action[T], len(actions))
+	for i, a := range actions {
+		sync[i] = Action[T]{Value: a.Value, Duration: 1}
+		if i == 0 {
+			continue
+		}
+		if a.At.Before(actions[i-1].At) {
+			return nil, errAnchorOrder
+		}
+		sync[i-1].Duration = a.At.Sub(actions[i-1].At)
+	}
+	sync[len(sync)-1].Tolerance = maxDuration
+	// The one nanosecond tail is not the user's doing, so only warn about small
+	// durations between instants.
+	_, warn := actionsDuration(sync[:len(sync)-1])
+	cfg.Iterations = 1
+	cfg.AlignTo = 0
+	g, err := NewGroupSync(sync, cfg)
+	if g == nil {
+		return nil, err
+	}
+	g.fixedStart = actions[0].At
+	return g, warn
+}
+
+// OffsetAction is an action that starts at an offset from the start of the iteration.
+type OffsetAction[T any] struct {
+	Offset time.Duration
+	Value  T
+}
+
+// NewGroupOffset returns a group whose actions start at offsets from the start
+// of each iteration instead of being chained by duration. Each iteration lasts
+// period. Offsets must not decrease and must be smaller than period. Idle periods
+// need no filler actions: an action stays current until the next action's offset,
+// and if the first offset is positive nothing is scheduled until then on the
+// first iteration. The group's StartTime is the time passed to Begin plus the first offset.
+func NewGroupOffset[T any](actions []OffsetAction[T], period time.Duration, cfg GroupSyncConfig) (*GroupSync[T], error) {
+	if len(actions) == 0 {
+		return nil, errEmptyActions
+	}
+	first := actions[0].Offset
+	if first < 0 {
+		return nil, errNegativeDuration
+	}
+	sync := make([]Action[T], len(actions))
+	for i, a := range actions {
+		end := period + first // Last action lasts until the first action of the next iteration.
+		if i+1 < len(actions) {
+			end = actions[i+1].Offset
+		}
+		if end < a.Offset || a.Offset >= period {
+			return nil, errOffsetOrder
+		}
+		sync[i] = Action[T]{Duration: end - a.Offset, Value: a.Value}
+	}
+	g, err := NewGroupSync(sync, cfg)
+	if g != nil {
+		g.startOffset = first
+	}
+	return g, err
+}
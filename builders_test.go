@@ -0,0 +1,217 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestRota(t *testing.T) {
+	const day = 24 * time.Hour
+	pattern := []actionInt{{Duration: 4 * day, Value: 1}, {Duration: 4 * day, Value: 0}}
+	groups, err := schedule.NewRota(pattern, 4, 2*day, schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	for _, g := range groups {
+		g.Begin(start)
+	}
+	// Expected values of entities for each day.
+	want := [][4]int{
+		{1, 1, 0, 0},
+		{1, 1, 0, 0},
+		{1, 0, 0, 1},
+		{1, 0, 0, 1},
+		{0, 0, 1, 1},
+		{0, 0, 1, 1},
+		{0, 1, 1, 0},
+		{0, 1, 1, 0},
+		{1, 1, 0, 0},
+	}
+	var current [4]int
+	for d, w := range want {
+		for i, g := range groups {
+			v, ok, _, err := g.ScheduleNext(start.Add(time.Duration(d) * day))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok {
+				current[i] = v
+			}
+		}
+		if current != w {
+			t.Errorf("day %d: got %v, want %v", d, current, w)
+		}
+	}
+}
+
+func TestIntervalTimer(t *testing.T) {
+	g, err := schedule.NewIntervalTimer(25*time.Minute, 5*time.Minute, 15*time.Minute, 4, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Duration() != 4*25*time.Minute+3*5*time.Minute+15*time.Minute {
+		t.Errorf("got duration %s", g.Duration())
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	var got []schedule.IntervalPhase
+	for now := start; ; now = now.Add(time.Minute) {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok && next == 0 {
+			break
+		}
+		if ok {
+			got = append(got, v)
+		}
+	}
+	want := []schedule.IntervalPhase{
+		schedule.PhaseWork, schedule.PhaseShortBreak, schedule.PhaseWork, schedule.PhaseShortBreak,
+		schedule.PhaseWork, schedule.PhaseShortBreak, schedule.PhaseWork, schedule.PhaseLongBreak,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got phases %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("phase %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	g, err := schedule.Every(5*time.Minute, 1, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	begin := time.Date(2024, 1, 1, 10, 3, 20, 0, time.UTC)
+	g.Begin(begin)
+	if want := time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC); !g.StartTime().Equal(want) {
+		t.Fatalf("got start %s, want %s", g.StartTime(), want)
+	}
+	_, ok, next, err := g.ScheduleNext(begin)
+	if err != nil || ok || next != time.Minute+40*time.Second {
+		t.Errorf("got ok=%t next=%s err=%v before aligned start, want waiting 1m40s", ok, next, err)
+	}
+	for i := 0; i < 3; i++ {
+		fire := g.StartTime().Add(time.Duration(i) * 5 * time.Minute)
+		if _, ok, _, err = g.ScheduleNext(fire); err != nil || !ok {
+			t.Errorf("got ok=%t err=%v at %s, want fire", ok, err, fire.Format(time.Kitchen))
+		}
+	}
+	// Already aligned start times are kept.
+	g.Begin(time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC))
+	if g.StartTime().Minute() != 5 {
+		t.Errorf("got start %s, want unchanged aligned start", g.StartTime())
+	}
+}
+
+func TestGroupAbsolute(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	actions := []schedule.AbsoluteAction[string]{
+		{At: t0, Value: "dim"},
+		{At: t0.Add(40 * time.Minute), Value: "off"},
+		{At: t0.Add(2 * time.Hour), Value: "lock"},
+	}
+	g, err := schedule.NewGroupAbsolute(actions, schedule.GroupSyncConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(t0.Add(-time.Hour))
+	_, ok, next, err := g.ScheduleNext(t0.Add(-time.Hour))
+	if err != nil || ok || next != time.Hour {
+		t.Errorf("got ok=%t next=%s err=%v, want waiting for first instant", ok, next, err)
+	}
+	for _, want := range actions {
+		v, ok, next, err := g.ScheduleNext(want.At)
+		if err != nil || !ok || v != want.Value {
+			t.Errorf("at %s got (%q, %t, %s, %v), want %q", want.At, v, ok, next, err, want.Value)
+		}
+	}
+	if _, ok, next, _ := g.ScheduleNext(t0.Add(3 * time.Hour)); ok || next != 0 {
+		t.Error("expected group done after last instant")
+	}
+
+	// The last instant is delivered when polled after it.
+	g.Begin(t0)
+	g.ScheduleNext(t0)
+	g.ScheduleNext(actions[1].At)
+	last := actions[2].At.Add(time.Millisecond)
+	if v, ok, _, err := g.ScheduleNext(last); err != nil || !ok || v != "lock" {
+		t.Errorf("got (%q, %t, %v) polling late, want last instant delivered", v, ok, err)
+	}
+	if _, ok, next, _ := g.ScheduleNext(last); ok || next != 0 {
+		t.Error("expected group done after late last instant")
+	}
+
+	// Starting late handles overdue instants according to the miss policy.
+	g.Begin(t0.Add(50 * time.Minute))
+	if _, _, _, err = g.ScheduleNext(t0.Add(50 * time.Minute)); !errors.Is(err, schedule.ErrMissedAction) {
+		t.Errorf("got %v, want ErrMissedAction for overdue instant", err)
+	}
+	g, err = schedule.NewGroupAbsolute(actions, schedule.GroupSyncConfig{OnMiss: schedule.MissSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(t0.Add(50 * time.Minute))
+	if v, ok, next, err := g.ScheduleNext(t0.Add(50 * time.Minute)); err != nil || !ok || v != "off" || next != 70*time.Minute {
+		t.Errorf("got (%q, %t, %s, %v), want overdue instant skipped", v, ok, next, err)
+	}
+
+	// A single instant is scheduled once.
+	g, err = schedule.NewGroupAbsolute(actions[:1], schedule.GroupSyncConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(t0.Add(-time.Minute))
+	if v, ok, _, err := g.ScheduleNext(t0.Add(time.Second)); err != nil || !ok || v != "dim" {
+		t.Errorf("got (%q, %t, %v), want single instant scheduled late", v, ok, err)
+	}
+	if _, ok, next, err := g.ScheduleNext(t0.Add(2 * time.Second)); err != nil || ok || next != 0 {
+		t.Error("expected group done after single instant")
+	}
+
+	actions[1].At = t0.Add(-time.Minute)
+	if _, err = schedule.NewGroupAbsolute(actions, schedule.GroupSyncConfig{}); err == nil {
+		t.Error("expected error for decreasing instants")
+	}
+}
+
+func TestGroupOffset(t *testing.T) {
+	actions := []schedule.OffsetAction[string]{
+		{Offset: 2 * time.Second, Value: "on"},
+		{Offset: 5 * time.Second, Value: "off"},
+	}
+	g, err := schedule.NewGroupOffset(actions, 10*time.Second, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	tests := []struct {
+		at   time.Duration
+		want string
+		next time.Duration
+	}{
+		{at: 0, next: 2 * time.Second},
+		{at: 2 * time.Second, want: "on", next: 3 * time.Second},
+		{at: 5 * time.Second, want: "off", next: 7 * time.Second},
+		{at: 12 * time.Second, want: "on", next: 3 * time.Second},
+		{at: 15 * time.Second, want: "off", next: 7 * time.Second},
+	}
+	for _, test := range tests {
+		v, ok, next, err := g.ScheduleNext(start.Add(test.at))
+		if err != nil || ok != (test.want != "") || v != test.want || next != test.next {
+			t.Errorf("at %s got (%q, %t, %s, %v), want %q next %s", test.at, v, ok, next, err, test.want, test.next)
+		}
+	}
+	if _, err = schedule.NewGroupOffset(actions[1:], 5*time.Second, schedule.GroupSyncConfig{Iterations: 1}); err == nil {
+		t.Error("expected error for offset outside period")
+	}
+}
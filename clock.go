@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+// tickEpoch is the time corresponding to tick zero. It must not be the zero
+// time.Time value since groups interpret a zero start time as not started.
+var tickEpoch = time.Unix(0, 0)
+
+// Ticks is the constraint for integer timestamps such as DSP sample counters,
+// hardware timer counts or field-bus epochs.
+type Ticks interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// TickGroup adapts a group to be driven by integer timestamps of type C
+// where each tick lasts a fixed period. Action durations of the wrapped
+// group are still specified as time.Duration.
+type TickGroup[T any, C Ticks] struct {
+	g      Grouper[T]
+	period time.Duration
+}
+
+// NewTickGroup returns a TickGroup that drives g with ticks of the given period.
+func NewTickGroup[T any, C Ticks](g Grouper[T], period time.Duration) (*TickGroup[T, C], error) {
+	if period <= 0 {
+		return nil, errBadTickPeriod
+	}
+	return &TickGroup[T, C]{g: g, period: period}, nil
+}
+
+// Begin sets the start tick of the group. See GroupSync.Begin.
+func (tg *TickGroup[T, C]) Begin(start C) {
+	tg.g.Begin(tg.Time(start))
+}
+
+// ScheduleNext works like GroupSync.ScheduleNext with `now` and `next` in ticks.
+// next is rounded up to the following tick so waiting next ticks never wakes
+// before the next action is ready.
+func (tg *TickGroup[T, C]) ScheduleNext(now C) (v T, ok bool, next C, err error) {
+	v, ok, nextD, err := tg.g.ScheduleNext(tg.Time(now))
+	return v, ok, tg.Ticks(nextD), err
+}
+
+// StartTick returns the tick the group was started at.
+func (tg *TickGroup[T, C]) StartTick() C {
+	return C(tg.g.StartTime().Sub(tickEpoch) / tg.period)
+}
+
+// Time converts a tick count to the time.Time passed to the wrapped group.
+func (tg *TickGroup[T, C]) Time(ticks C) time.Time {
+	return tickEpoch.Add(time.Duration(ticks) * tg.period)
+}
+
+// Ticks converts a duration to ticks rounding up to the next whole tick.
+func (tg *TickGroup[T, C]) Ticks(d time.Duration) C {
+	return C((d + tg.period - 1) / tg.period)
+}
+
+// FrameAction is an action whose duration is measured in sample frames.
+type FrameAction[T any] struct {
+	Frames int64
+	Value  T
+}
+
+// NewFrameGroup returns a synchronized group driven by a sample frame counter,
+// such as the position of an audio stream, so envelopes and sequences can be
+// scheduled with zero rounding against the audio clock. now and next are
+// measured in frames. Internally each frame is a nanosecond of virtual time,
+// so times observed by cfg.Discipline and cfg.Recorder are virtual as well.
+// Use FramesOf to convert durations to frames at a sample rate.
+func NewFrameGroup[T any](actions []FrameAction[T], cfg GroupSyncConfig) (*TickGroup[T, int64], error) {
+	virtual := make([]Action[T], len(actions))
+	for i, a := range actions {
+		virtual[i] = Action[T]{Duration: time.Duration(a.Frames), Value: a.Value}
+	}
+	g, err := NewGroupSync(virtual, cfg)
+	if err != nil && !errors.Is(err, ErrSmallDuration) {
+		return nil, err // Frames are not subject to event loop resolution.
+	}
+	return NewTickGroup[T, int64](g, time.Nanosecond)
+}
+
+// FramesOf converts a duration to the nearest number of sample frames at sampleRate
+// frames per second.
+func FramesOf(d time.Duration, sampleRate int) int64 {
+	// Split seconds and remainder to avoid overflowing on long durations.
+	sec, rem := int64(d/time.Second), int64(d%time.Second)
+	return sec*int64(sampleRate) + (rem*int64(sampleRate)+int64(time.Second)/2)/int64(time.Second)
+}
+
+// MillisClock converts readings of a free running uint32 millisecond counter,
+// typical of RTC and SysTick peripherals, into time.Time values that can be
+// passed to Begin and ScheduleNext. The counter wraps around every ~49.7 days;
+// MillisClock keeps track of wraparounds so schedules may be longer than
+// the counter period as long as Time is called at least once per period
+// with monotonically increasing readings. The zero value is ready to use.
+type MillisClock struct {
+	elapsed time.Duration
+	last    uint32
+	started bool
+}
+
+// Time returns the time corresponding to the millisecond counter reading ms.
+func (c *MillisClock) Time(ms uint32) time.Time {
+	if !c.started {
+		c.started = true
+		c.elapsed = time.Duration(ms) * time.Millisecond
+	} else {
+		// Unsigned subtraction yields correct difference across wraparound.
+		c.elapsed += time.Duration(ms-c.last) * time.Millisecond
+	}
+	c.last = ms
+	return tickEpoch.Add(c.elapsed)
+}
+
+// Millis converts a duration such as the next value returned by ScheduleNext
+// to milliseconds rounding up to the next whole millisecond.
+func (c *MillisClock) Millis(d time.Duration) uint32 {
+	return uint32((d + time.Millisecond - 1) / time.Millisecond)
+}
+
+// Discipliner corrects local timestamps using an external time reference such as
+// a GPS 1PPS signal or NTP, so that groups on distributed devices stay aligned.
+// Implementations must return monotonically increasing times for monotonically
+// increasing local times.
+type Discipliner interface {
+	// Correct maps a local timestamp to the reference timescale.
+	Correct(local time.Time) time.Time
+}
+
+// PPSDiscipline is a Discipliner driven by a one pulse per second signal.
+// It measures the local duration of each reference second and scales local
+// time elapsed since the last pulse accordingly, correcting both offset and
+// local clock rate error. Missing pulses are tolerated. The zero value is
+// ready to use and returns uncorrected times until the first pulse.
+type PPSDiscipline struct {
+	// ref is the reference time of the last pulse.
+	ref time.Time
+	// local is the local time at which the last pulse was received.
+	local time.Time
+	// second is the measured local duration of a reference second.
+	second time.Duration
+}
+
+// Pulse registers a pulse received at local time. The first pulse defines the
+// reference timescale to be equal to local time at that instant.
+func (d *PPSDiscipline) Pulse(local time.Time) {
+	if d.local.IsZero() {
+		d.ref, d.local, d.second = local, local, time.Second
+		return
+	}
+	sinceLast := local.Sub(d.local)
+	seconds := (sinceLast + d.second/2) / d.second // Round to account for missed pulses.
+	if seconds <= 0 {
+		return // Spurious pulse.
+	}
+	d.ref = d.ref.Add(seconds * time.Second)
+	d.local = local
+	d.second = sinceLast / seconds
+}
+
+// Correct returns the reference time corresponding to local time.
+func (d *PPSDiscipline) Correct(local time.Time) time.Time {
+	if d.local.IsZero() {
+		return local
+	}
+	sinceLast := local.Sub(d.local)
+	return d.ref.Add(time.Duration(float64(sinceLast) * float64(time.Second) / float64(d.second)))
+}
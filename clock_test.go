@@ -0,0 +1,155 @@
+package schedule_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestTickGroup(t *testing.T) {
+	type sampleCount uint64
+	const period = time.Millisecond
+	actions := []actionInt{{Duration: 100 * period, Value: 1}, {Duration: 5500 * time.Microsecond, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg, err := schedule.NewTickGroup[int, sampleCount](g, period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const start = 1000
+	tg.Begin(start)
+	if got := tg.StartTick(); got != start {
+		t.Errorf("got start tick %d, want %d", got, start)
+	}
+	for _, test := range []struct {
+		now      sampleCount
+		wantV    int
+		wantOK   bool
+		wantNext sampleCount
+	}{
+		{now: start - 5, wantNext: 5},
+		{now: start, wantV: 1, wantOK: true, wantNext: 100},
+		{now: start + 100, wantV: 2, wantOK: true, wantNext: 6}, // 5.5 ticks rounded up.
+		{now: start + 106, wantNext: 0},
+	} {
+		v, ok, next, err := tg.ScheduleNext(test.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != test.wantV || ok != test.wantOK || next != test.wantNext {
+			t.Errorf("now=%d: got v=%d ok=%v next=%d, want v=%d ok=%v next=%d", test.now, v, ok, next, test.wantV, test.wantOK, test.wantNext)
+		}
+	}
+}
+
+func TestMillisClockWraparound(t *testing.T) {
+	actions := []actionInt{{Duration: 200 * time.Millisecond, Value: 1}, {Duration: 200 * time.Millisecond, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clock schedule.MillisClock
+	start := uint32(math.MaxUint32 - 250)
+	g.Begin(clock.Time(start))
+	var fired []int
+	for ms := start; ; ms += 10 {
+		v, ok, next, err := g.ScheduleNext(clock.Time(ms))
+		if err != nil {
+			t.Fatalf("ms=%d: %v", ms, err)
+		}
+		if ok {
+			fired = append(fired, v)
+		} else if next == 0 {
+			if elapsed := ms - start; elapsed != 400 {
+				t.Errorf("group done after %dms across wraparound, want 400ms", elapsed)
+			}
+			break
+		}
+		if got := clock.Millis(next); got == 0 || got > 200 {
+			t.Fatalf("ms=%d: bad next %dms", ms, got)
+		}
+	}
+	if len(fired) != 2 {
+		t.Errorf("got %d actions fired, want 2", len(fired))
+	}
+}
+
+func TestPPSDiscipline(t *testing.T) {
+	// Local clock runs 0.1% fast and starts at an arbitrary offset.
+	const localSecond = time.Second + time.Millisecond
+	localStart := time.Unix(1000, 0)
+	var pps schedule.PPSDiscipline
+	if got := pps.Correct(localStart); !got.Equal(localStart) {
+		t.Error("expected uncorrected time before first pulse")
+	}
+	for i := 0; i < 5; i++ {
+		if i == 2 {
+			continue // Missed pulse.
+		}
+		pps.Pulse(localStart.Add(time.Duration(i) * localSecond))
+	}
+	got := pps.Correct(localStart.Add(4*localSecond + localSecond/2))
+	want := localStart.Add(4*time.Second + time.Second/2)
+	if diff := got.Sub(want); diff < -time.Microsecond || diff > time.Microsecond {
+		t.Errorf("got disciplined time off by %s", diff)
+	}
+
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Discipline: &pps})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(localStart.Add(4 * localSecond))
+	_, _, _, err = g.ScheduleNext(localStart.Add(4 * localSecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Local clock has run over a second but the reference clock has not.
+	_, ok, _, err := g.ScheduleNext(localStart.Add(5*localSecond - 500*time.Microsecond))
+	if err != nil || ok {
+		t.Errorf("expected no action before disciplined second elapsed, got ok=%v err=%v", ok, err)
+	}
+	v, ok, _, err := g.ScheduleNext(localStart.Add(5 * localSecond))
+	if err != nil || !ok || v != 2 {
+		t.Errorf("expected second action after one disciplined second, got v=%d ok=%v err=%v", v, ok, err)
+	}
+}
+
+func TestFrameGroup(t *testing.T) {
+	const rate = 44100
+	attack := schedule.FramesOf(10*time.Millisecond, rate)
+	if attack != 441 {
+		t.Fatalf("got %d frames for 10ms at %dHz, want 441", attack, rate)
+	}
+	actions := []schedule.FrameAction[int]{{Frames: attack, Value: 1}, {Frames: 1, Value: 2}, {Frames: 3, Value: 3}}
+	g, err := schedule.NewFrameGroup(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const start = 1 << 40
+	g.Begin(start)
+	for _, test := range []struct {
+		frame    int64
+		v        int
+		ok       bool
+		nextWant int64
+	}{
+		{start, 1, true, 441},
+		{start + 440, 0, false, 1},
+		{start + 441, 2, true, 1},
+		{start + 442, 3, true, 3},
+		{start + 445, 0, false, 0},
+	} {
+		v, ok, next, err := g.ScheduleNext(test.frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != test.v || ok != test.ok || next != test.nextWant {
+			t.Errorf("frame %d: got (%d, %t, %d), want (%d, %t, %d)", test.frame-start, v, ok, next, test.v, test.ok, test.nextWant)
+		}
+	}
+}
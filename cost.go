@@ -0,0 +1,69 @@
+package schedule
+
+import "time"
+
+// CostMeter integrates a cost rate associated with each action value, such as
+// power drawn in watts, over the actual run time of a GroupSync's actions.
+// Integrated costs are expressed in rate units times seconds, i.e. joules for watts.
+// Use CostMeter's ScheduleNext in place of the group's to account cost.
+type CostMeter[T any] struct {
+	g    *GroupSync[T]
+	rate func(T) float64
+	// lastUpdate is the time cost was last integrated up to.
+	lastUpdate time.Time
+	// activeRate is the rate of the running action.
+	activeRate float64
+	iter       int
+	total      float64
+	iterTotal  float64
+	lastTotal  float64
+}
+
+// NewCostMeter returns a CostMeter accounting the cost of the actions of g
+// with rate returning the cost rate of an action value.
+func NewCostMeter[T any](g *GroupSync[T], rate func(v T) float64) *CostMeter[T] {
+	return &CostMeter[T]{g: g, rate: rate}
+}
+
+// Begin calls Begin on the group and resets the meter.
+func (m *CostMeter[T]) Begin(start time.Time) {
+	m.g.Begin(start)
+	*m = CostMeter[T]{g: m.g, rate: m.rate}
+}
+
+// ScheduleNext calls ScheduleNext on the group and integrates the cost of the
+// running action up to now.
+func (m *CostMeter[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	m.integrate(now)
+	v, ok, next, err = m.g.ScheduleNext(now)
+	switch {
+	case ok:
+		if iter := m.g.lastPos / len(m.g.actions); iter != m.iter {
+			m.lastTotal, m.iterTotal = m.iterTotal, 0
+			m.iter = iter
+		}
+		m.activeRate = m.rate(v)
+		m.lastUpdate = now
+	case next == 0 || err != nil:
+		m.activeRate = 0 // Group done or failed.
+	}
+	return v, ok, next, err
+}
+
+func (m *CostMeter[T]) integrate(now time.Time) {
+	if !m.lastUpdate.IsZero() && now.After(m.lastUpdate) {
+		cost := m.activeRate * now.Sub(m.lastUpdate).Seconds()
+		m.total += cost
+		m.iterTotal += cost
+	}
+	m.lastUpdate = now
+}
+
+// Total returns the cost accumulated since Begin was called.
+func (m *CostMeter[T]) Total() float64 { return m.total }
+
+// IterationTotal returns the cost accumulated during the current iteration
+// and the last completed iteration.
+func (m *CostMeter[T]) IterationTotal() (current, last float64) {
+	return m.iterTotal, m.lastTotal
+}
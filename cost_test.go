@@ -0,0 +1,39 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestCostMeter(t *testing.T) {
+	// Heater on at 100W for one second, off for three seconds.
+	actions := []schedule.Action[bool]{{Duration: time.Second, Value: true}, {Duration: 3 * time.Second, Value: false}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter := schedule.NewCostMeter(g, func(on bool) float64 {
+		if on {
+			return 100
+		}
+		return 0
+	})
+	start := time.Unix(0, 0)
+	meter.Begin(start)
+	for elapsed := time.Duration(0); elapsed <= 12*time.Second; elapsed += 100 * time.Millisecond {
+		if _, _, _, err := meter.ScheduleNext(start.Add(elapsed)); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed == 6*time.Second {
+			current, last := meter.IterationTotal()
+			if current != 100 || last != 100 {
+				t.Errorf("got iteration totals %g and %g, want 100J each", current, last)
+			}
+		}
+	}
+	if total := meter.Total(); total != 300 {
+		t.Errorf("got total cost %gJ, want 300J", total)
+	}
+}
@@ -0,0 +1,128 @@
+// Package dmx builds schedules of DMX512 lighting cues and outputs them
+// to a DMX interface at a fixed refresh rate.
+package dmx
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+// Channels is the number of channels of a DMX512 universe.
+const Channels = 512
+
+// Frame holds the levels of all channels of a DMX512 universe.
+type Frame [Channels]byte
+
+// Cue is a lighting state faded into over Fade and held for Hold.
+type Cue struct {
+	Levels Frame
+	Fade   time.Duration
+	Hold   time.Duration
+}
+
+var (
+	errBadRefresh = errors.New("zero or negative DMX refresh period")
+	errBadCue     = errors.New("negative cue fade or hold time")
+)
+
+// Build returns a schedule of frames that fades from the initial levels through
+// each cue in turn. Fades are rendered as interpolated steps lasting one refresh
+// period each, the last step possibly shorter, and holds as a single action.
+func Build(initial Frame, cues []Cue, refresh time.Duration) ([]schedule.Action[Frame], error) {
+	if refresh <= 0 {
+		return nil, errBadRefresh
+	}
+	var actions []schedule.Action[Frame]
+	from := initial
+	for _, cue := range cues {
+		if cue.Fade < 0 || cue.Hold < 0 {
+			return nil, errBadCue
+		}
+		for t := refresh; t < cue.Fade; t += refresh {
+			// Step starting at t-refresh shows the levels reached at t.
+			actions = append(actions, schedule.Action[Frame]{
+				Duration: refresh,
+				Value:    interpolate(from, cue.Levels, t, cue.Fade),
+			})
+		}
+		if cue.Fade > 0 {
+			last := cue.Fade % refresh
+			if last == 0 {
+				last = refresh
+			}
+			actions = append(actions, schedule.Action[Frame]{Duration: last, Value: cue.Levels})
+		}
+		if cue.Hold > 0 {
+			actions = append(actions, schedule.Action[Frame]{Duration: cue.Hold, Value: cue.Levels})
+		}
+		from = cue.Levels
+	}
+	return actions, nil
+}
+
+func interpolate(from, to Frame, t, total time.Duration) (f Frame) {
+	for i := range f {
+		delta := int64(to[i]) - int64(from[i])
+		f[i] = byte(int64(from[i]) + delta*int64(t)/int64(total))
+	}
+	return f
+}
+
+// Output writes frames to a DMX interface. DMX receivers expect frames to be
+// refreshed continuously so the current frame is written on every Step even
+// when the schedule does not change it. The break preceding each frame
+// is expected to be generated by the interface.
+type Output struct {
+	w     io.Writer
+	frame Frame
+	buf   [1 + Channels]byte
+}
+
+// NewOutput returns an Output that writes frames to w, starting from an all
+// zero frame.
+func NewOutput(w io.Writer) *Output {
+	return &Output{w: w}
+}
+
+// frameGroup is the method set required of groups driving an Output.
+type frameGroup interface {
+	Begin(time.Time)
+	ScheduleNext(time.Time) (v Frame, ok bool, next time.Duration, err error)
+}
+
+// Step schedules g at now and writes the current frame, with a null start code.
+// done is true once g is done.
+func (o *Output) Step(g frameGroup, now time.Time) (done bool, err error) {
+	v, ok, next, err := g.ScheduleNext(now)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		o.frame = v
+	}
+	o.buf[0] = 0 // Null start code for dimmer data.
+	copy(o.buf[1:], o.frame[:])
+	if _, err = o.w.Write(o.buf[:]); err != nil {
+		return false, err
+	}
+	return !ok && next == 0, nil
+}
+
+// Run begins g and writes frames every refresh period until g is done or fails.
+func (o *Output) Run(g frameGroup, refresh time.Duration) error {
+	if refresh <= 0 {
+		return errBadRefresh
+	}
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	g.Begin(time.Now())
+	for now := time.Now(); ; now = <-ticker.C {
+		done, err := o.Step(g, now)
+		if done || err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package dmx_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+	"github.com/soypat/schedule/dmx"
+)
+
+func TestBuild(t *testing.T) {
+	const refresh = 25 * time.Millisecond
+	var full dmx.Frame
+	full[0] = 200
+	actions, err := dmx.Build(dmx.Frame{}, []dmx.Cue{
+		{Levels: full, Fade: 90 * time.Millisecond, Hold: time.Second},
+		{Levels: dmx.Frame{}, Hold: time.Second}, // Snap to blackout.
+	}, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		level byte
+		d     time.Duration
+	}{
+		{55, refresh}, {111, refresh}, {166, refresh}, {200, 15 * time.Millisecond},
+		{200, time.Second},
+		{0, time.Second},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("got %d actions, want %d", len(actions), len(want))
+	}
+	for i, w := range want {
+		if actions[i].Value[0] != w.level || actions[i].Duration != w.d {
+			t.Errorf("action %d: got level %d for %s, want %d for %s", i, actions[i].Value[0], actions[i].Duration, w.level, w.d)
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	var on dmx.Frame
+	on[3] = 255
+	g, err := schedule.NewGroupSync([]schedule.Action[dmx.Frame]{{Duration: 50 * time.Millisecond, Value: on}}, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	out := dmx.NewOutput(&buf)
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for i, now := range []time.Time{start, start.Add(25 * time.Millisecond), start.Add(50 * time.Millisecond)} {
+		done, err := out.Step(g, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done != (i == 2) {
+			t.Errorf("step %d: got done=%t", i, done)
+		}
+	}
+	if buf.Len() != 3*(1+dmx.Channels) {
+		t.Fatalf("got %d bytes written, want 3 frames", buf.Len())
+	}
+	if frame := buf.Bytes()[1+dmx.Channels:]; frame[0] != 0 || frame[4] != 255 {
+		t.Errorf("got start code %d and channel 4 level %d, want 0 and 255", frame[0], frame[4])
+	}
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import "time"
+
+// Event bundles the result of a ScheduleNext call with metadata of the scheduled action.
+type Event[T any] struct {
+	// Value is the value of the scheduled action if Ok is true.
+	Value T
+	// Ok is true if an action was scheduled.
+	Ok bool
+	// Done is true once the group is done.
+	Done bool
+	// Next is the time until the next action is ready, as returned by ScheduleNext.
+	Next time.Duration
+	// Name is the name of the scheduled action. It is empty if Ok is false or
+	// the action was injected.
+	Name string
+	// TriggerID identifies the scheduled action's firing. It is the zero value if Ok is false.
+	TriggerID
+	// Scheduled is the time the action was due. The zero time is returned if Ok
+	// is false or the time is unknown, see GroupSync.IterationBoundary.
+	Scheduled time.Time
+	// Lateness is the time elapsed between the time the action was due and the
+	// time it was scheduled at.
+	Lateness time.Duration
+	// Backfilled is true if the action was missed and replayed. See MissReplay.
+	Backfilled bool
+	// Collapsed is the number of missed actions coalesced into the action. See MissSkip.
+	Collapsed int
+	// Injected is true if the action was injected with GroupSync.Inject or
+	// GroupSync.Interrupt, in which case TriggerID is the zero value and
+	// Scheduled is the injection time.
+	Injected bool
+}
+
+// ScheduleNextEvent works like ScheduleNext but returns the result as an Event
+// carrying metadata of the scheduled action.
+func (g *GroupSync[T]) ScheduleNextEvent(now time.Time) (ev Event[T], err error) {
+	ev.Value, ev.Ok, ev.Next, err = g.ScheduleNext(now)
+	ev.Done = !ev.Ok && ev.Next == 0 && err == nil
+	if !ev.Ok {
+		return ev, err
+	}
+	if g.discipline != nil {
+		now = g.discipline.Correct(now)
+	}
+	if g.injectedLast {
+		ev.Injected = true
+		ev.Scheduled = g.lastInjection
+		ev.Lateness = now.Sub(ev.Scheduled)
+		return ev, err
+	}
+	ev.TriggerID, _ = g.LastTrigger()
+	ev.Backfilled = g.backfilled
+	ev.Collapsed = g.collapsed
+	ev.Name = g.actions[g.lastPos%len(g.actions)].Name
+	ev.Scheduled = g.lastDue
+	if !ev.Scheduled.IsZero() {
+		ev.Lateness = now.Sub(ev.Scheduled)
+	}
+	return ev, err
+}
+
+// ScheduleNextEvent works like ScheduleNext but returns the result as an Event
+// carrying metadata of the scheduled action. Since GroupLoose does not miss
+// actions Backfilled and Collapsed are always the zero value.
+func (g *GroupLoose[T]) ScheduleNextEvent(now time.Time) (ev Event[T], err error) {
+	ev.Value, ev.Ok, ev.Next, err = g.ScheduleNext(now)
+	ev.Done = !ev.Ok && ev.Next == 0 && err == nil
+	if !ev.Ok {
+		return ev, err
+	}
+	n := len(g.actions)
+	ev.TriggerID = TriggerID{Iteration: g.lastIdx / n, Index: g.lastIdx % n, Seq: g.lastIdx}
+	ev.Name = g.actions[g.lastIdx%n].Name
+	ev.Lateness = g.lastLateness
+	ev.Scheduled = now.Add(-g.lastLateness)
+	return ev, err
+}
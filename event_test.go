@@ -0,0 +1,94 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestScheduleNextEvent(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 10, Name: "a"}, {Duration: time.Second, Value: 20, Name: "b"}, {Duration: time.Second, Value: 30}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2, OnMiss: schedule.MissSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	ev, err := g.ScheduleNextEvent(start.Add(100 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ev.Ok || ev.Value != 10 || ev.Name != "a" || ev.Index != 0 || ev.Iteration != 0 || ev.Lateness != 100*time.Millisecond || !ev.Scheduled.Equal(start) {
+		t.Errorf("got first event %+v", ev)
+	}
+	ev, err = g.ScheduleNextEvent(start.Add(4200 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := schedule.TriggerID{Iteration: 1, Index: 1, Seq: 4}
+	if !ev.Ok || ev.Value != 20 || ev.Name != "b" || ev.TriggerID != want || ev.Collapsed != 3 || ev.Lateness != 200*time.Millisecond || ev.Next != 800*time.Millisecond {
+		t.Errorf("got event %+v after missed actions", ev)
+	}
+	ev, err = g.ScheduleNextEvent(start.Add(4500 * time.Millisecond))
+	if err != nil || ev.Ok || ev.Done {
+		t.Errorf("got event %+v, err %v while waiting", ev, err)
+	}
+	ev, err = g.ScheduleNextEvent(start.Add(6 * time.Second))
+	if err != nil || !ev.Done {
+		t.Errorf("got event %+v, err %v after group end", ev, err)
+	}
+}
+
+func TestScheduleNextEventLoose(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 10}, {Duration: time.Second, Value: 20}}
+	g, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNextEvent(start)
+	now := start.Add(1300 * time.Millisecond)
+	ev, err := g.ScheduleNextEvent(now)
+	want := schedule.TriggerID{Iteration: 0, Index: 1, Seq: 1}
+	if err != nil || !ev.Ok || ev.Value != 20 || ev.TriggerID != want || ev.Lateness != 300*time.Millisecond || !ev.Scheduled.Equal(start.Add(time.Second)) {
+		t.Errorf("got event %+v, err %v", ev, err)
+	}
+	ev, _ = g.ScheduleNextEvent(now.Add(time.Second))
+	if ev.Iteration != 1 || ev.Index != 0 || ev.Lateness != 0 {
+		t.Errorf("got event %+v on second iteration", ev)
+	}
+}
+
+func TestScheduleNextEventLate(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	start := time.Unix(100, 0)
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, CompleteLate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNextEvent(start)
+	ev, err := g.ScheduleNextEvent(start.Add(1300 * time.Millisecond))
+	if err != nil || !ev.Ok || ev.Value != 2 || ev.Lateness != 300*time.Millisecond || !ev.Scheduled.Equal(start.Add(time.Second)) {
+		t.Errorf("got event %+v, err %v for action completed late", ev, err)
+	}
+
+	pressureOK := false
+	guard := func(index int) bool { return index != 1 || pressureOK }
+	g, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Guard: guard, OnGuard: schedule.GuardHold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNextEvent(start)
+	for _, poll := range []time.Duration{1100, 1900, 2500} {
+		g.ScheduleNextEvent(start.Add(poll * time.Millisecond))
+	}
+	pressureOK = true
+	ev, err = g.ScheduleNextEvent(start.Add(2600 * time.Millisecond))
+	if err != nil || !ev.Ok || ev.Value != 2 || ev.Lateness != 1600*time.Millisecond || !ev.Scheduled.Equal(start.Add(time.Second)) {
+		t.Errorf("got event %+v, err %v for held action", ev, err)
+	}
+}
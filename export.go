@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ChromeTrace collects executed actions and writes them as Chrome trace-event
+// JSON which can be inspected in Perfetto or chrome://tracing alongside other
+// system traces. Each group is displayed as a track and each action as a slice.
+// The zero value is ready to use.
+type ChromeTrace struct {
+	epoch  time.Time
+	events []traceEvent
+	tracks map[string]int
+}
+
+type traceEvent struct {
+	Name     string            `json:"name"`
+	Phase    string            `json:"ph"`
+	Time     float64           `json:"ts"`
+	Duration float64           `json:"dur,omitempty"`
+	PID      int               `json:"pid"`
+	TID      int               `json:"tid"`
+	Args     map[string]string `json:"args,omitempty"`
+}
+
+// Add records an action of group with the given name that started at start
+// and ran for duration. When called after ScheduleNext returns ok the next
+// return value is the duration the action runs for. The first recorded
+// start time is used as the origin of the trace.
+func (c *ChromeTrace) Add(group, name string, start time.Time, duration time.Duration) {
+	if c.tracks == nil {
+		c.tracks = make(map[string]int)
+		c.epoch = start
+	}
+	tid, ok := c.tracks[group]
+	if !ok {
+		tid = len(c.tracks) + 1
+		c.tracks[group] = tid
+		c.events = append(c.events, traceEvent{
+			Name: "thread_name", Phase: "M", PID: 1, TID: tid,
+			Args: map[string]string{"name": group},
+		})
+	}
+	c.events = append(c.events, traceEvent{
+		Name:     name,
+		Phase:    "X",
+		Time:     micros(start.Sub(c.epoch)),
+		Duration: micros(duration),
+		PID:      1,
+		TID:      tid,
+	})
+}
+
+// AddValue records an action like Add, naming the slice after value. Values
+// implementing fmt.Stringer, such as enums, use their string form.
+func (c *ChromeTrace) AddValue(group string, value any, start time.Time, duration time.Duration) {
+	c.Add(group, formatValue(value), start, duration)
+}
+
+// WriteTo writes the recorded actions to w in the Chrome trace-event JSON format.
+func (c *ChromeTrace) WriteTo(w io.Writer) (int64, error) {
+	events := c.events
+	if events == nil {
+		events = []traceEvent{}
+	}
+	b, err := json.Marshal(struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}{TraceEvents: events})
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// formatValue formats an action value for logs and traces. Values implementing
+// fmt.Stringer, such as enums, use their string form. Common basic types are
+// formatted without reflection.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// CSVWriter writes timestamped action values as CSV rows with the columns
+// time, group, action and value, suitable for import into spreadsheets and pandas.
+// Timestamps are formatted as RFC3339 with nanoseconds.
+type CSVWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVWriter returns a CSVWriter that writes to w. The header row is
+// written before the first record.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write writes a row for the action of group with the given name and value
+// fired at t. Values implementing fmt.Stringer are written in their string form. Rows are flushed after
+// every write so they are not lost if the program stops.
+func (c *CSVWriter) Write(t time.Time, group, name string, value any) error {
+	if !c.headerWritten {
+		if err := c.w.Write([]string{"time", "group", "action", "value"}); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	err := c.w.Write([]string{t.Format(time.RFC3339Nano), group, name, formatValue(value)})
+	if err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,115 @@
+package schedule_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestChromeTrace(t *testing.T) {
+	actions := []actionInt{{Duration: time.Millisecond, Value: 1}, {Duration: 2 * time.Millisecond, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace schedule.ChromeTrace
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for now := start; ; now = now.Add(time.Millisecond / 2) {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok && next == 0 {
+			break
+		}
+		if ok {
+			trace.Add("group", string(rune('a'+v)), now, next)
+		}
+	}
+	trace.Add("other", "x", start, time.Millisecond)
+	var sb strings.Builder
+	if _, err = trace.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		TraceEvents []struct {
+			Name string  `json:"name"`
+			Ph   string  `json:"ph"`
+			Ts   float64 `json:"ts"`
+			Dur  float64 `json:"dur"`
+			Tid  int     `json:"tid"`
+		} `json:"traceEvents"`
+	}
+	if err = json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	var slices int
+	tracks := map[int]bool{}
+	for _, ev := range got.TraceEvents {
+		if ev.Ph != "X" {
+			continue
+		}
+		slices++
+		tracks[ev.Tid] = true
+		if ev.Name == "c" && (ev.Ts != 1000 || ev.Dur != 2000) {
+			t.Errorf("got slice %q at %gus for %gus, want 1000us for 2000us", ev.Name, ev.Ts, ev.Dur)
+		}
+	}
+	if slices != 3 || len(tracks) != 2 {
+		t.Errorf("got %d slices on %d tracks, want 3 slices on 2 tracks", slices, len(tracks))
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var sb strings.Builder
+	w := schedule.NewCSVWriter(&sb)
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := w.Write(start, "valve", "open", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(start.Add(time.Second/2), "valve", "close, slowly", 0.5); err != nil {
+		t.Fatal(err)
+	}
+	const want = "time,group,action,value\n" +
+		"2024-01-02T03:04:05Z,valve,open,1\n" +
+		"2024-01-02T03:04:05.5Z,valve,\"close, slowly\",0.5\n"
+	if got := sb.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+type valve uint8
+
+func (v valve) String() string {
+	if v == 1 {
+		return "VALVE_OPEN"
+	}
+	return "VALVE_CLOSED"
+}
+
+func TestStringerValues(t *testing.T) {
+	var sb strings.Builder
+	w := schedule.NewCSVWriter(&sb)
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := w.Write(start, "tank", "fill", valve(1)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(sb.String(), ",VALVE_OPEN\n") {
+		t.Errorf("got CSV %q, want Stringer form of value", sb.String())
+	}
+
+	rec := schedule.NewRecorder(4)
+	g, err := schedule.NewGroupSync([]schedule.Action[valve]{{Duration: time.Second, Value: 1}}, schedule.GroupSyncConfig{Iterations: 1, Recorder: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNext(start)
+	if d := rec.Decisions(); len(d) != 1 || d[0].Value != "VALVE_OPEN" {
+		t.Errorf("got decisions %+v, want recorded value VALVE_OPEN", d)
+	}
+}
@@ -0,0 +1,31 @@
+package schedule
+
+import "time"
+
+// Grouper is the method set shared by groups with fixed duration iterations
+// so code can be written generic over the scheduling strategy. It is
+// implemented by GroupSync, GroupLoose, GroupNested, GroupSelect and GroupGated.
+type Grouper[T any] interface {
+	// Begin sets the start time of the group. It must be called before ScheduleNext
+	// and resets internal state of the group so it can be reused.
+	Begin(start time.Time)
+	// ScheduleNext returns the next action value v when ok is true and the
+	// duration until the next action is ready. When ok is false and next
+	// is zero the group is done.
+	ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error)
+	// Duration returns how long a single iteration lasts.
+	Duration() time.Duration
+	// StartTime returns the time the group was started at.
+	StartTime() time.Time
+	// Iterations returns the number of times the group runs, -1 for infinite iterations.
+	Iterations() int
+}
+
+// Groups must share the same control surface.
+var (
+	_ Grouper[int] = (*GroupSync[int])(nil)
+	_ Grouper[int] = (*GroupLoose[int])(nil)
+	_ Grouper[int] = (*GroupNested[int])(nil)
+	_ Grouper[int] = (*GroupSelect[int])(nil)
+	_ Grouper[int] = (*GroupGated[int])(nil)
+)
@@ -0,0 +1,128 @@
+package schedule
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+var (
+	errBadFactor = errors.New("backoff factor must be at least 1")
+	errBadJitter = errors.New("jitter must be between 0 and 1")
+	errBadMax    = errors.New("backoff max delay smaller than initial delay")
+)
+
+type GroupBackoffConfig struct {
+	// Initial is the delay after the first attempt. Must be greater than zero.
+	Initial time.Duration
+	// Factor multiplies the delay after each attempt. Zero value means 2.
+	Factor float64
+	// Max caps the delay between attempts. Zero value means no cap.
+	// If set it must not be smaller than Initial.
+	Max time.Duration
+	// Jitter randomizes each delay by up to the given fraction of it in either
+	// direction, so that many devices retrying at once spread out. Must be between 0 and 1.
+	Jitter float64
+	// Seed seeds the random number generator used for jitter.
+	Seed int64
+	// Attempts is the number of attempts before the group is done.
+	// Must be greater than zero or -1 to indicate infinite attempts.
+	Attempts int
+}
+
+// GroupBackoff schedules the same value repeatedly with exponentially growing
+// delays between attempts, such as retries of a failed request. The first attempt
+// is scheduled at the start time and each following attempt is scheduled a delay
+// after the call that scheduled the previous attempt.
+type GroupBackoff[T any] struct {
+	value  T
+	cfg    GroupBackoffConfig
+	rng    *rand.Rand
+	start  time.Time
+	nextAt time.Time
+	// delay is the delay following the next attempt before jitter.
+	delay   time.Duration
+	attempt int
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupBackoff returns a backoff group scheduling value.
+func NewGroupBackoff[T any](value T, cfg GroupBackoffConfig) (*GroupBackoff[T], error) {
+	if cfg.Factor == 0 {
+		cfg.Factor = 2
+	}
+	switch {
+	case cfg.Initial <= 0 || cfg.Max < 0:
+		return nil, errNegativeDuration
+	case cfg.Max != 0 && cfg.Max < cfg.Initial:
+		return nil, errBadMax
+	case cfg.Factor < 1:
+		return nil, errBadFactor
+	case cfg.Jitter < 0 || cfg.Jitter > 1:
+		return nil, errBadJitter
+	case cfg.Attempts <= 0 && cfg.Attempts != -1:
+		return nil, errBadIterations
+	}
+	return &GroupBackoff[T]{value: value, cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}, nil
+}
+
+// Begin sets the start time of the group and reseeds its random number generator.
+// It must be called before ScheduleNext and resets the delay to its initial value.
+// A zero start time anchors the start to the time passed to the first ScheduleNext call.
+func (g *GroupBackoff[T]) Begin(start time.Time) {
+	g.start = start
+	g.lazy = start.IsZero()
+	g.nextAt = start
+	g.delay = g.cfg.Initial
+	g.attempt = 0
+	g.rng.Seed(g.cfg.Seed)
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupBackoff[T]) StartTime() time.Time {
+	return g.start
+}
+
+// Attempt returns the number of attempts scheduled since Begin.
+func (g *GroupBackoff[T]) Attempt() int {
+	return g.attempt
+}
+
+// ScheduleNext returns the group's value when ok is true and the delay until the
+// next attempt. If ok is false and next is zero all attempts were scheduled.
+func (g *GroupBackoff[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy && g.start.IsZero() {
+		g.Begin(now)
+	}
+	if g.start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	if g.cfg.Attempts != -1 && g.attempt >= g.cfg.Attempts {
+		return v, false, 0, nil // Done.
+	}
+	if wait := g.nextAt.Sub(now); wait > 0 {
+		return v, false, wait, nil // Still waiting for next attempt.
+	}
+	next = g.delay
+	if g.cfg.Jitter > 0 {
+		next = scaleDuration(next, 1+g.cfg.Jitter*(2*g.rng.Float64()-1))
+	}
+	g.delay = scaleDuration(g.delay, g.cfg.Factor)
+	if g.cfg.Max > 0 && g.delay > g.cfg.Max {
+		g.delay = g.cfg.Max
+	}
+	g.nextAt = now.Add(next)
+	g.attempt++
+	return g.value, true, next, nil
+}
+
+// scaleDuration returns d scaled by factor, saturating at maxDuration
+// instead of overflowing.
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	scaled := float64(d) * factor
+	if scaled >= float64(maxDuration) {
+		return maxDuration
+	}
+	return time.Duration(scaled)
+}
@@ -0,0 +1,87 @@
+package schedule_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupBackoff(t *testing.T) {
+	g, err := schedule.NewGroupBackoff("retry", schedule.GroupBackoffConfig{
+		Initial:  time.Second,
+		Max:      5 * time.Second,
+		Attempts: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	var delays []time.Duration
+	now := start
+	for {
+		_, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok && next == 0 {
+			break
+		}
+		if ok {
+			delays = append(delays, next)
+		}
+		now = now.Add(next)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if len(delays) != len(want) || g.Attempt() != 5 {
+		t.Fatalf("got delays %v, want %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("got delays %v, want %v", delays, want)
+		}
+	}
+
+	g, err = schedule.NewGroupBackoff("retry", schedule.GroupBackoffConfig{Initial: time.Second, Jitter: 0.5, Seed: 1, Attempts: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	delay := time.Second
+	for i := 0; i < 10; i++ {
+		_, ok, next, _ := g.ScheduleNext(now)
+		if !ok || next < delay/2 || next > delay*3/2 {
+			t.Fatalf("attempt %d got ok=%t delay %s, want %s within jitter", i, ok, next, delay)
+		}
+		now = now.Add(next)
+		delay *= 2
+	}
+	if _, err = schedule.NewGroupBackoff("retry", schedule.GroupBackoffConfig{Initial: 10 * time.Second, Max: 5 * time.Second, Attempts: 1}); err == nil {
+		t.Error("expected error for max delay smaller than initial delay")
+	}
+}
+
+func TestGroupBackoffOverflow(t *testing.T) {
+	for _, jitter := range []float64{0, 0.5} {
+		g, err := schedule.NewGroupBackoff("retry", schedule.GroupBackoffConfig{Initial: time.Second, Jitter: jitter, Seed: 1, Attempts: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Unix(100, 0)
+		g.Begin(now)
+		var next time.Duration
+		for i := 0; i < 100; i++ {
+			var ok bool
+			_, ok, next, _ = g.ScheduleNext(now)
+			if !ok || next <= 0 {
+				t.Fatalf("jitter %g attempt %d got ok=%t delay %s, want positive delay", jitter, i, ok, next)
+			}
+			now = now.Add(next)
+		}
+		if jitter == 0 && next != math.MaxInt64 {
+			t.Errorf("got delay %s after many retries, want saturated delay", next)
+		}
+	}
+}
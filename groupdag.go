@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errDependencyCycle = errors.New("dependency cycle between actions")
+	errBadDependency   = errors.New("dependency on nonexistent action")
+)
+
+// Node is an action of a GroupDAG along with its dependencies.
+type Node[T any] struct {
+	Action[T]
+	// Offset is the earliest time after the group's start the action may start.
+	Offset time.Duration
+	// After holds the indices of the actions that must complete before the action starts.
+	After []int
+}
+
+// GroupDAG schedules actions ordered by dependencies instead of a linear list.
+// An action becomes eligible once all actions it depends on completed, i.e.
+// their durations elapsed, and its offset elapsed. Independent actions may
+// run concurrently. GroupDAG runs once and is done when all actions completed.
+// It has the timing semantics of GroupLoose: actions start when scheduled.
+type GroupDAG[T any] struct {
+	nodes []Node[T]
+	start time.Time
+	// started holds the time each action was scheduled at, zero if not scheduled.
+	started []time.Time
+	lastIdx int
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupDAG returns a group scheduling nodes by their dependencies.
+// An error is returned if the dependencies contain a cycle.
+func NewGroupDAG[T any](nodes []Node[T]) (*GroupDAG[T], error) {
+	if len(nodes) == 0 {
+		return nil, errEmptyActions
+	}
+	for _, node := range nodes {
+		if node.Duration < 0 || node.Offset < 0 {
+			return nil, errNegativeDuration
+		}
+		for _, dep := range node.After {
+			if dep < 0 || dep >= len(nodes) {
+				return nil, errBadDependency
+			}
+		}
+	}
+	// Detect cycles by repeatedly removing actions without pending dependencies.
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, node := range nodes {
+		pending[i] = len(node.After)
+		for _, dep := range node.After {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+	var ready []int
+	for i, p := range pending {
+		if p == 0 {
+			ready = append(ready, i)
+		}
+	}
+	removed := 0
+	for len(ready) > 0 {
+		i := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		removed++
+		for _, dependent := range dependents[i] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if removed != len(nodes) {
+		return nil, errDependencyCycle
+	}
+	return &GroupDAG[T]{nodes: nodes, started: make([]time.Time, len(nodes)), lastIdx: -1}, nil
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupDAG[T]) Begin(start time.Time) {
+	g.start = start
+	g.lazy = start.IsZero()
+	g.lastIdx = -1
+	for i := range g.started {
+		g.started[i] = time.Time{}
+	}
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupDAG[T]) StartTime() time.Time {
+	return g.start
+}
+
+// LastIndex returns the index of the last scheduled action or -1 if no action
+// was scheduled since Begin.
+func (g *GroupDAG[T]) LastIndex() int {
+	return g.lastIdx
+}
+
+// ScheduleNext returns the value of an eligible action when ok is true. Eligible
+// actions are scheduled in index order and next is zero while more actions are
+// eligible, in which case ScheduleNext should be called again. Otherwise next
+// is the time until an action becomes eligible or, once all actions were
+// scheduled, until the last action completes. If ok is false and next is zero
+// the group is done.
+func (g *GroupDAG[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy && g.start.IsZero() {
+		g.Begin(now)
+	}
+	if g.start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	scheduled := -1
+	for i, node := range g.nodes {
+		var wait time.Duration
+		if !g.started[i].IsZero() {
+			// Scheduled actions keep the group running until they complete.
+			wait = g.started[i].Add(node.Duration).Sub(now)
+		} else if eligibleAt, known := g.eligibleAt(i); !known {
+			continue // Dependencies not yet scheduled.
+		} else if wait = eligibleAt.Sub(now); wait <= 0 && scheduled == -1 {
+			scheduled = i
+			g.started[i] = now
+			g.lastIdx = i
+			if wait = node.Duration; wait == 0 {
+				return node.Value, true, 0, nil // Dependents may be eligible now.
+			}
+		} else if wait <= 0 {
+			return g.nodes[scheduled].Value, true, 0, nil // More actions eligible.
+		}
+		if wait > 0 && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+	if scheduled == -1 {
+		return v, false, next, nil
+	}
+	return g.nodes[scheduled].Value, true, next, nil
+}
+
+// eligibleAt returns the time the action at index i becomes eligible. known is
+// false if any of its dependencies was not scheduled.
+func (g *GroupDAG[T]) eligibleAt(i int) (at time.Time, known bool) {
+	at = g.start.Add(g.nodes[i].Offset)
+	for _, dep := range g.nodes[i].After {
+		if g.started[dep].IsZero() {
+			return at, false
+		}
+		if end := g.started[dep].Add(g.nodes[dep].Duration); end.After(at) {
+			at = end
+		}
+	}
+	return at, true
+}
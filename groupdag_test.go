@@ -0,0 +1,61 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupDAG(t *testing.T) {
+	node := func(name string, d time.Duration, after ...int) schedule.Node[string] {
+		return schedule.Node[string]{Action: schedule.Action[string]{Duration: d, Value: name}, After: after}
+	}
+	nodes := []schedule.Node[string]{
+		node("mix", 3*time.Second, 1, 2),
+		node("heat", 2*time.Second),
+		node("fill", time.Second),
+		node("pour", time.Second, 0),
+	}
+	nodes[2].Offset = 500 * time.Millisecond
+	g, err := schedule.NewGroupDAG(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	type fired struct {
+		at   time.Duration
+		name string
+	}
+	var got []fired
+	now := start
+	for {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			got = append(got, fired{now.Sub(start), v})
+		} else if next == 0 {
+			break
+		}
+		now = now.Add(next)
+	}
+	want := []fired{{0, "heat"}, {500 * time.Millisecond, "fill"}, {2 * time.Second, "mix"}, {5 * time.Second, "pour"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if end := now.Sub(start); end != 6*time.Second {
+		t.Errorf("got end %s, want 6s", end)
+	}
+	nodes[1].After = []int{3}
+	if _, err = schedule.NewGroupDAG(nodes); err == nil {
+		t.Error("expected error for dependency cycle")
+	}
+}
@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errGateTimeout      = errors.New("gated action timed out waiting for trigger")
+	errBadTimeoutPolicy = errors.New("invalid timeout policy")
+)
+
+// TimeoutPolicy specifies how a GroupGated handles gated actions whose duration
+// elapses before a trigger is received.
+type TimeoutPolicy uint8
+
+const (
+	// TimeoutContinue continues with the next action as if the trigger was received. This is the default.
+	TimeoutContinue TimeoutPolicy = iota
+	// TimeoutFail fails the group. ScheduleNext returns errors until Begin is called again.
+	TimeoutFail
+)
+
+type GroupGatedConfig struct {
+	// Iterations specifies how many times to run the group. Must be greater than zero
+	// or -1 to indicate infinite iterations.
+	Iterations int
+	// Gated reports whether the action at index waits for a trigger.
+	Gated func(index int) bool
+	// OnTimeout specifies how gated actions that time out are handled.
+	// The default is TimeoutContinue.
+	OnTimeout TimeoutPolicy
+}
+
+// GroupGated is a group where gated actions wait for an external trigger, such
+// as a handshake with hardware that signals readiness. A gated action runs until
+// Trigger is called or its duration, the timeout, elapses. A trigger ends the
+// action early and the next action is scheduled on the following call to ScheduleNext.
+// Otherwise GroupGated has the timing semantics of GroupLoose.
+type GroupGated[T any] struct {
+	g         *GroupLoose[T]
+	gated     func(index int) bool
+	onTimeout TimeoutPolicy
+	triggered bool
+	failed    bool
+}
+
+// NewGroupGated returns a newly initialized gated group.
+func NewGroupGated[T any](actions []Action[T], cfg GroupGatedConfig) (*GroupGated[T], error) {
+	if cfg.OnTimeout > TimeoutFail {
+		return nil, errBadTimeoutPolicy
+	}
+	g, err := NewGroupLoose(actions, GroupLooseConfig{Iterations: cfg.Iterations})
+	if err != nil {
+		return nil, err
+	}
+	gated := cfg.Gated
+	if gated == nil {
+		gated = func(int) bool { return false }
+	}
+	return &GroupGated[T]{g: g, gated: gated, onTimeout: cfg.OnTimeout}, nil
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupGated[T]) Begin(start time.Time) {
+	g.g.Begin(start)
+	g.triggered = false
+	g.failed = false
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupGated[T]) StartTime() time.Time { return g.g.StartTime() }
+
+// Duration returns the time it takes to run all actions of the group when no
+// trigger is received.
+func (g *GroupGated[T]) Duration() time.Duration { return g.g.Duration() }
+
+// Iterations returns the number of iterations the group will run for.
+// It may be -1 for infinite iterations.
+func (g *GroupGated[T]) Iterations() int { return g.g.Iterations() }
+
+// Trigger signals the gated action running, if any, to end. Triggers received
+// while no gated action is running are ignored.
+func (g *GroupGated[T]) Trigger() {
+	g.triggered = true
+}
+
+// ScheduleNext works like GroupLoose.ScheduleNext. While a gated action runs next
+// is the time until it times out, so ScheduleNext should be called frequently
+// enough to react to triggers.
+func (g *GroupGated[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.failed {
+		return v, false, 0, ErrGroupFailed
+	}
+	gl := g.g
+	if idx := gl.lastIdx; !gl.start.IsZero() && idx >= 0 && g.gated(idx%len(gl.actions)) {
+		running := now.Sub(gl.lastActionStart)
+		switch {
+		case g.triggered && running < gl.lastDuration:
+			gl.lastDuration = running // End gated action now.
+		case !g.triggered && running >= gl.lastDuration && g.onTimeout == TimeoutFail:
+			g.failed = true
+			n := len(gl.actions)
+			return v, false, 0, &ActionError{Index: idx % n, Iteration: idx / n, Name: gl.actions[idx%n].Name, Err: errGateTimeout}
+		}
+	}
+	v, ok, next, err = gl.ScheduleNext(now)
+	if ok {
+		g.triggered = false // Triggers only apply to the running action.
+	}
+	return v, ok, next, err
+}
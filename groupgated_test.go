@@ -0,0 +1,54 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupGated(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: 5 * time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	for _, onTimeout := range []schedule.TimeoutPolicy{schedule.TimeoutContinue, schedule.TimeoutFail} {
+		g, err := schedule.NewGroupGated(actions, schedule.GroupGatedConfig{
+			Iterations: 1,
+			Gated:      func(index int) bool { return index == 1 },
+			OnTimeout:  onTimeout,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Unix(100, 0)
+		g.Begin(start)
+		g.ScheduleNext(start)
+		g.ScheduleNext(start.Add(time.Second))
+		if _, ok, next, _ := g.ScheduleNext(start.Add(2 * time.Second)); ok || next != 4*time.Second {
+			t.Errorf("got ok=%t next=%s, want waiting for trigger", ok, next)
+		}
+		g.Trigger()
+		v, ok, _, err := g.ScheduleNext(start.Add(2500 * time.Millisecond))
+		if err != nil || !ok || v != 3 {
+			t.Errorf("got (%d, %t, %v), want action following triggered action", v, ok, err)
+		}
+
+		// Without trigger the gated action times out.
+		g.Begin(start)
+		g.ScheduleNext(start)
+		g.ScheduleNext(start.Add(time.Second))
+		v, ok, _, err = g.ScheduleNext(start.Add(6 * time.Second))
+		switch onTimeout {
+		case schedule.TimeoutContinue:
+			if err != nil || !ok || v != 3 {
+				t.Errorf("got (%d, %t, %v), want action following timed out action", v, ok, err)
+			}
+		case schedule.TimeoutFail:
+			if err == nil || ok {
+				t.Errorf("got ok=%t err=%v, want timeout error", ok, err)
+			}
+			if _, _, _, err = g.ScheduleNext(start.Add(7 * time.Second)); !errors.Is(err, schedule.ErrGroupFailed) {
+				t.Errorf("got %v, want ErrGroupFailed", err)
+			}
+		}
+	}
+}
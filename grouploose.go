@@ -2,6 +2,8 @@ package schedule
 
 import (
 	"errors"
+	"math"
+	"math/rand"
 	"time"
 )
 
@@ -9,11 +11,29 @@ type GroupLooseConfig struct {
 	// Iterations specifies how many times to run the group. Must be greater than zero
 	// or -1 to indicate infinite iterations.
 	Iterations int
+	// StopOnBoundary makes StopAt and StopAfter let the action running at the
+	// stop time complete before the group reports done. By default the group
+	// reports done as soon as the stop time is reached.
+	StopOnBoundary bool
+	// MaxLate is the maximum time an action may be triggered after it was due
+	// before the group fails. Zero value means actions may be triggered arbitrarily late.
+	MaxLate time.Duration
+	// CatchUp shortens the actions that follow a late trigger by the time lost
+	// so that the iteration still ends on time. Time lost that can't be recovered
+	// before the iteration ends is forgotten so drift is bounded to one iteration.
+	CatchUp bool
+	// Jitter randomizes the duration of each scheduled action by up to Jitter in
+	// either direction, drawn anew every time the action is scheduled, to avoid
+	// synchronized bursts when many devices run the same schedule.
+	Jitter time.Duration
+	// Seed seeds the random number generator used for jitter. The sequence of
+	// jitter restarts every time Begin is called.
+	Seed int64
 }
 
 // NewGroupLoose returns a newly initialized loose timing group.
 func NewGroupLoose[T any](actions []Action[T], cfg GroupLooseConfig) (*GroupLoose[T], error) {
-	duration, err := actionsDuration(actions, true)
+	duration, err := actionsDuration(actions)
 	switch {
 	case err != nil && !errors.Is(err, ErrSmallDuration):
 		return nil, err
@@ -21,37 +41,149 @@ func NewGroupLoose[T any](actions []Action[T], cfg GroupLooseConfig) (*GroupLoos
 		return nil, errEmptyActions
 	case cfg.Iterations <= 0 && cfg.Iterations != -1:
 		return nil, errBadIterations
+	case cfg.MaxLate < 0 || cfg.Jitter < 0:
+		return nil, errNegativeDuration
+	}
+	if cfg.Iterations != -1 {
+		if _, err := iterationsDuration(duration, cfg.Iterations); err != nil {
+			return nil, err
+		}
 	}
 
 	g := &GroupLoose[T]{
-		actions:    actions,
-		duration:   duration,
-		iterations: cfg.Iterations,
+		actions:        actions,
+		duration:       duration,
+		iterations:     cfg.Iterations,
+		stopOnBoundary: cfg.StopOnBoundary,
+		maxLate:        cfg.MaxLate,
+		catchUp:        cfg.CatchUp,
+		jitter:         cfg.Jitter,
+		seed:           cfg.Seed,
+	}
+	if cfg.Jitter > 0 {
+		g.rng = rand.New(rand.NewSource(cfg.Seed))
 	}
 	return g, nil // ignore ErrSmallDuration for loose groups.
 }
 
+// MustGroupLoose is like NewGroupLoose but panics if the group is invalid. It is
+// meant for initializing package level variables with static schedules.
+func MustGroupLoose[T any](actions []Action[T], cfg GroupLooseConfig) *GroupLoose[T] {
+	g, err := NewGroupLoose(actions, cfg)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
 // GroupLoose specifies a group of actions that should be executed one after another.
 // Use GroupLoose when synchonizing between groups is not a priority and when action
 // durations may be very small. Some observations on GroupLoose's usage:
 //
-//   - Each action is guaranteed to run for at least it's duration.
-//   - There is no penalty for triggering an action late. GroupLoose will not fail.
+//   - Each action is guaranteed to run for at least it's duration unless CatchUp or Jitter is configured.
+//   - There is no penalty for triggering an action late unless MaxLate is configured.
+//     In that case the group fails if an action is triggered later than MaxLate
+//     after it was due and errors will be returned until Begin is called again.
 type GroupLoose[T any] struct {
 	start           time.Time
 	lastActionStart time.Time
 	duration        time.Duration
-	lastIdx         int
-	actions         []Action[T]
-	iterations      int
+	// lastDuration is the duration of the last scheduled action which may be
+	// shorter than the action's duration when catching up.
+	lastDuration time.Duration
+	lastIdx      int
+	actions      []Action[T]
+	iterations   int
+	// stop is the time at which the group reports done. Zero value means no stop.
+	stop           time.Time
+	stopOnBoundary bool
+	maxLate        time.Duration
+	failed         bool
+	catchUp        bool
+	// debt is time lost to late triggers not yet recovered by catching up.
+	debt time.Duration
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+	// lazyStop is the StopAfter duration set before a lazy start, -1 if none.
+	lazyStop time.Duration
+	// lastLateness is the lateness of the last scheduled action.
+	lastLateness time.Duration
+	jitter       time.Duration
+	seed         int64
+	rng          *rand.Rand
 }
 
-// Begins sets the start time of the group. It must be called before ScheduleNext.
-// It effectively resets internal state of the group.
-func (g *GroupLoose[T]) Begins(start time.Time) {
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupLoose[T]) Begin(start time.Time) {
 	g.start = start
+	g.lazy = start.IsZero()
+	g.lazyStop = -1
 	g.lastActionStart = time.Time{}
 	g.lastIdx = -1
+	g.stop = time.Time{}
+	g.failed = false
+	g.debt = 0
+	if g.rng != nil {
+		g.rng.Seed(g.seed)
+	}
+}
+
+// Begins is an alias of Begin.
+//
+// Deprecated: Use Begin.
+func (g *GroupLoose[T]) Begins(start time.Time) {
+	g.Begin(start)
+}
+
+// AddIterations extends the number of iterations of the group by n while
+// preserving its phase. It has no effect on groups with infinite iterations.
+// It must be called before the group is done. An error wrapping ErrDurationOverflow
+// is returned if the extended group's total duration would overflow.
+func (g *GroupLoose[T]) AddIterations(n int) error {
+	if n <= 0 {
+		return errBadIterations
+	}
+	if g.iterations == -1 {
+		return nil
+	}
+	if n > math.MaxInt-g.iterations {
+		return ErrDurationOverflow
+	}
+	if _, err := iterationsDuration(g.duration, g.iterations+n); err != nil {
+		return err
+	}
+	g.iterations += n
+	return nil
+}
+
+// FinishIteration lets the group complete the iteration of the most recently
+// scheduled action and then report done. It is meant for winding down infinite
+// groups without stopping between actions. After the call Iterations
+// returns the new, finite, iteration count.
+func (g *GroupLoose[T]) FinishIteration() {
+	g.iterations = 1
+	if g.lastIdx > 0 {
+		g.iterations += g.lastIdx / len(g.actions)
+	}
+}
+
+// StopAt arranges for the group to report done at time t. If the group was
+// configured with StopOnBoundary the action running at t is allowed to complete.
+// It must be called after Begin, which clears any previously set stop time.
+func (g *GroupLoose[T]) StopAt(t time.Time) {
+	g.stop = t
+}
+
+// StopAfter arranges for the group to report done a duration d after the group's
+// start time. It is equivalent to calling StopAt(g.StartTime().Add(d)).
+func (g *GroupLoose[T]) StopAfter(d time.Duration) {
+	if g.lazy {
+		g.lazyStop = d // Applied once the start time is known.
+		return
+	}
+	g.StopAt(g.start.Add(d))
 }
 
 // StartTime time returns the time the group was Started at. If not started returns zero value.
@@ -71,43 +203,131 @@ func (g *GroupLoose[T]) Duration() time.Duration {
 	return g.duration
 }
 
+// RequiredResolution returns the maximum interval between ScheduleNext calls
+// that triggers actions no later than the shortest non-zero action duration.
+// GroupLoose does not fail when polled slower, but actions are delayed and
+// the delay accumulates over the group's runtime.
+func (g *GroupLoose[T]) RequiredResolution() time.Duration {
+	return minDuration(g.actions)
+}
+
+// IterationsRemaining returns the number of iterations left to run at time now,
+// counting the iteration in progress. It returns -1 for groups with infinite
+// iterations and 0 once the group is done.
+func (g *GroupLoose[T]) IterationsRemaining(now time.Time) int {
+	if g.iterations == -1 {
+		return -1
+	}
+	if g.lastIdx == -1 {
+		return g.iterations
+	}
+	completed := g.lastIdx / len(g.actions)
+	if now.Sub(g.lastActionStart) >= g.lastDuration {
+		completed = (g.lastIdx + 1) / len(g.actions) // Last scheduled action is done.
+	}
+	if completed >= g.iterations {
+		return 0
+	}
+	return g.iterations - completed
+}
+
 // ScheduleNext checks `now` against time GroupLoose started and returns
 // the next executable action when `ok` is true and `next` duration until next
 // ready action.
 //
 // If ok is false and next is zero the group is done.
 func (g *GroupLoose[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy {
+		// Anchor the start keeping state set up after Begin.
+		g.start, g.lazy = now, false
+		if g.lazyStop >= 0 {
+			g.stop = now.Add(g.lazyStop)
+		}
+	}
 	if g.start.IsZero() {
-		return v, false, 0, errBeginNotCalled
+		return v, false, 0, ErrBeginNotCalled
+	}
+	if g.failed {
+		return v, false, 0, ErrGroupFailed
+	}
+	hasStop := !g.stop.IsZero()
+	pastStop := hasStop && !now.Before(g.stop)
+	if pastStop && !g.stopOnBoundary {
+		return v, false, 0, nil // Stopped.
 	}
 	elapsed := now.Sub(g.start)
 	if elapsed < 0 {
-		return v, false, -elapsed, nil // Still waiting for start time.
+		return v, false, stopCap(now, g.stop, g.stopOnBoundary, -elapsed), nil // Still waiting for start time.
 	}
 
 	if g.lastIdx == -1 {
 		// Special case for first action.
-		g.lastActionStart = now
-		g.lastIdx = 0
-		return g.actions[0].Value, true, g.actions[0].Duration, nil
+		if pastStop {
+			return v, false, 0, nil // Stopped on action boundary.
+		}
+		if err = g.checkLate(0, elapsed); err != nil {
+			return v, false, 0, err
+		}
+		v, next = g.startAction(now, elapsed)
+		return v, true, stopCap(now, g.stop, g.stopOnBoundary, next), nil
 	}
 	actionElapsed := now.Sub(g.lastActionStart)
-	safeIdx := g.lastIdx % len(g.actions)
-	currAction := g.actions[safeIdx]
-
-	if actionElapsed < currAction.Duration {
-		return v, false, currAction.Duration - actionElapsed, nil // Still waiting for next action.
+	if actionElapsed < g.lastDuration {
+		return v, false, stopCap(now, g.stop, g.stopOnBoundary, g.lastDuration-actionElapsed), nil // Still waiting for next action.
 	}
 	nextIdx := g.lastIdx + 1
 	nextActionEnabled := g.iterations == -1 || nextIdx < len(g.actions)*g.iterations
-	if !nextActionEnabled {
+	if !nextActionEnabled || pastStop {
 		return v, false, 0, nil // Done.
 	}
+	lateness := actionElapsed - g.lastDuration
+	if err = g.checkLate(nextIdx, lateness); err != nil {
+		return v, false, 0, err
+	}
+	v, next = g.startAction(now, lateness)
+	return v, true, stopCap(now, g.stop, g.stopOnBoundary, next), nil
+}
+
+// startAction schedules the action following the last scheduled action at now
+// and returns its value and the duration it will run for.
+func (g *GroupLoose[T]) startAction(now time.Time, lateness time.Duration) (T, time.Duration) {
 	g.lastIdx++
 	g.lastActionStart = now
-	safeIdx = g.lastIdx % len(g.actions)
+	g.lastLateness = lateness
+	safeIdx := g.lastIdx % len(g.actions)
 	// We return the full time of the action duration when we start it since we
 	// guarantee each action will take at least it's duration to complete.
 	// This is the same guarantee that time.Sleep provides with regards to the sleep duration.
-	return g.actions[safeIdx].Value, true, g.actions[safeIdx].Duration, nil
+	// When catching up the duration is shortened to recover time lost to late triggers.
+	g.lastDuration = g.actions[safeIdx].Duration
+	if g.jitter > 0 {
+		g.lastDuration += time.Duration((2*g.rng.Float64() - 1) * float64(g.jitter))
+		if g.lastDuration < 0 {
+			g.lastDuration = 0
+		}
+	}
+	if g.catchUp {
+		if safeIdx == 0 {
+			g.debt = 0 // Forget time lost in previous iteration.
+		}
+		g.debt += lateness
+		recovered := g.debt
+		if recovered > g.lastDuration {
+			recovered = g.lastDuration
+		}
+		g.lastDuration -= recovered
+		g.debt -= recovered
+	}
+	return g.actions[safeIdx].Value, g.lastDuration
+}
+
+// checkLate fails the group if lateness of the action at idx exceeds the
+// configured maximum lateness.
+func (g *GroupLoose[T]) checkLate(idx int, lateness time.Duration) error {
+	if g.maxLate > 0 && lateness > g.maxLate {
+		g.failed = true
+		n := len(g.actions)
+		return &ActionError{Index: idx % n, Iteration: idx / n, Name: g.actions[idx%n].Name, Err: errTooLate}
+	}
+	return nil
 }
@@ -0,0 +1,247 @@
+package schedule
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Phase is an outer step of a GroupNested consisting of an inner cycle of
+// actions repeated Repeat times.
+type Phase[T any] struct {
+	Inner  []Action[T]
+	Repeat int
+}
+
+type GroupNestedConfig struct {
+	// Iterations specifies how many times to run the group. Must be greater than zero
+	// or -1 to indicate infinite iterations.
+	Iterations int
+}
+
+// NewGroupNested returns a newly initialized nested group. Each phase must have a
+// positive Repeat count and an inner cycle with a duration greater than zero.
+func NewGroupNested[T any](phases []Phase[T], cfg GroupNestedConfig) (*GroupNested[T], error) {
+	if len(phases) == 0 {
+		return nil, errEmptyActions
+	}
+	if cfg.Iterations <= 0 && cfg.Iterations != -1 {
+		return nil, errBadIterations
+	}
+	g := &GroupNested[T]{
+		phases:     phases,
+		inner:      make([]nestedPhase, len(phases)),
+		iterations: cfg.Iterations,
+	}
+	var warn error
+	for i, phase := range phases {
+		innerDuration, err := actionsDuration(phase.Inner)
+		if errors.Is(err, ErrSmallDuration) {
+			warn, err = err, nil
+		}
+		switch {
+		case err != nil:
+			return nil, err
+		case len(phase.Inner) == 0:
+			return nil, errEmptyActions
+		case innerDuration == 0:
+			return nil, errZeroDuration
+		case phase.Repeat <= 0:
+			return nil, errBadIterations
+		}
+		phaseDuration, err := iterationsDuration(innerDuration, phase.Repeat)
+		if err != nil {
+			return nil, err
+		}
+		offsets, _, _ := actionOffsets(phase.Inner, time.Time{})
+		g.inner[i] = nestedPhase{
+			offsets:  offsets,
+			duration: innerDuration,
+			start:    g.duration,
+			startPos: g.actionsPerIter,
+		}
+		if phaseDuration > maxDuration-g.duration {
+			return nil, ErrDurationOverflow
+		}
+		g.duration += phaseDuration
+		g.actionsPerIter += phase.Repeat * len(phase.Inner)
+	}
+	if cfg.Iterations != -1 {
+		if _, err := iterationsDuration(g.duration, cfg.Iterations); err != nil {
+			return nil, err
+		}
+	}
+	return g, warn // return ErrSmallDuration as a warning to users.
+}
+
+// GroupNested is a GroupSync-like group with multi-rate periodicity: each outer
+// phase runs a fast inner cycle of actions a fixed number of times. This allows
+// schedules such as a 10Hz stimulus inside one minute phases without flattening
+// them into thousands of actions. GroupNested shares the miss semantics
+// of GroupSync: if an inner action is not scheduled during its allotted time
+// the group fails and errors are returned until Begin is called again.
+type GroupNested[T any] struct {
+	start time.Time
+	// elapsedToRestart is the time from start to the start of the iteration restartIter.
+	elapsedToRestart time.Duration
+	restartIter      int
+	duration         time.Duration
+	// lastPos is the position of the last scheduled action counting the actions
+	// of previous iterations and phase repetitions.
+	lastPos        int
+	actionsPerIter int
+	phases         []Phase[T]
+	inner          []nestedPhase
+	iterations     int
+	failed         bool
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// nestedPhase holds precomputed timing information of a Phase.
+type nestedPhase struct {
+	// offsets of inner actions relative to the start of the inner cycle.
+	offsets []time.Duration
+	// duration of a single inner cycle.
+	duration time.Duration
+	// start of the phase relative to the start of the iteration.
+	start time.Duration
+	// startPos is the position of the first action of the phase within an iteration.
+	startPos int
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupNested[T]) Begin(start time.Time) {
+	g.start = start
+	g.lazy = start.IsZero()
+	g.elapsedToRestart = 0
+	g.restartIter = 0
+	g.lastPos = -1
+	g.failed = false
+}
+
+// StartTime time returns the time the group was Started at. If not started returns zero value.
+func (g *GroupNested[T]) StartTime() time.Time {
+	return g.start
+}
+
+// Duration returns the time it takes to run all phases of the group once.
+func (g *GroupNested[T]) Duration() time.Duration {
+	return g.duration
+}
+
+// Iterations returns the number of iterations the group will run for.
+// It may be -1 for infinite iterations.
+func (g *GroupNested[T]) Iterations() int {
+	return g.iterations
+}
+
+// RequiredResolution returns the maximum interval between ScheduleNext calls
+// that guarantees no action is missed, which is the shortest inner action duration.
+func (g *GroupNested[T]) RequiredResolution() (min time.Duration) {
+	for _, phase := range g.phases {
+		if d := minDuration(phase.Inner); d > 0 && (min == 0 || d < min) {
+			min = d
+		}
+	}
+	return min
+}
+
+// ScheduleNext checks `now` against time GroupNested started and returns
+// the next executable action when `ok` is true and `next` duration until next
+// ready action.
+//
+// If ok is false and next is zero the group is done. If ok is true and next is zero
+// a zero duration action was scheduled and ScheduleNext should be called again.
+func (g *GroupNested[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy {
+		g.start, g.lazy = now, false
+	}
+	if g.start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	if g.failed {
+		return v, false, 0, ErrGroupFailed
+	}
+	elapsed := now.Sub(g.start)
+	if elapsed < 0 {
+		return v, false, -elapsed, nil // Still waiting for start time.
+	}
+	elapsed -= g.elapsedToRestart
+	if elapsed >= g.duration {
+		completed := elapsed / g.duration
+		g.elapsedToRestart += completed * g.duration
+		g.restartIter += int(completed)
+		elapsed -= completed * g.duration
+	}
+	done := g.iterations != -1 && g.restartIter >= g.iterations
+	pos := g.iterations * g.actionsPerIter // Position past last action for finite groups.
+	if !done {
+		var idx int
+		idx, next = g.currentIdx(elapsed)
+		pos = g.restartIter*g.actionsPerIter + idx
+	}
+	if pos == g.lastPos {
+		return v, false, next, nil // Still need to execute current action.
+	}
+	expected := g.lastPos + 1
+	if expected < pos && g.onlyZeroDuration(expected, pos) {
+		// Zero duration actions are scheduled back to back with the action that follows them.
+		g.lastPos = expected
+		return g.actionAt(expected).Value, true, 0, nil
+	}
+	if done {
+		return v, false, 0, nil // We are done, time exceeded.
+	}
+	if pos != expected {
+		g.failed = true
+		return v, false, 0, g.actionError(expected, ErrMissedAction) // Missed action.
+	}
+	g.lastPos = pos
+	return g.actionAt(pos).Value, true, next, nil
+}
+
+// currentIdx returns the position within an iteration of the last action that
+// started at or before elapsed and the time until the following action starts.
+func (g *GroupNested[T]) currentIdx(elapsed time.Duration) (int, time.Duration) {
+	p := sort.Search(len(g.inner), func(i int) bool { return g.inner[i].start > elapsed }) - 1
+	phase := &g.inner[p]
+	elapsed -= phase.start
+	rep := int(elapsed / phase.duration)
+	elapsed -= time.Duration(rep) * phase.duration
+	idx := sort.Search(len(phase.offsets), func(i int) bool { return phase.offsets[i] > elapsed }) - 1
+	nextStart := phase.duration
+	if idx+1 < len(phase.offsets) {
+		nextStart = phase.offsets[idx+1]
+	}
+	return phase.startPos + rep*len(phase.offsets) + idx, nextStart - elapsed
+}
+
+// actionAt returns the action at position pos.
+func (g *GroupNested[T]) actionAt(pos int) Action[T] {
+	pos %= g.actionsPerIter
+	p := sort.Search(len(g.inner), func(i int) bool { return g.inner[i].startPos > pos }) - 1
+	inner := g.phases[p].Inner
+	return inner[(pos-g.inner[p].startPos)%len(inner)]
+}
+
+// actionError returns err annotated with the action at position pos.
+func (g *GroupNested[T]) actionError(pos int, err error) error {
+	n := g.actionsPerIter
+	return &ActionError{Index: pos % n, Iteration: pos / n, Name: g.actionAt(pos).Name, Err: err}
+}
+
+// onlyZeroDuration reports whether all actions in positions [start, end) have zero duration.
+func (g *GroupNested[T]) onlyZeroDuration(start, end int) bool {
+	if end-start > g.actionsPerIter {
+		return false
+	}
+	for pos := start; pos < end; pos++ {
+		if g.actionAt(pos).Duration != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+	"golang.org/x/exp/rand"
+)
+
+func TestGroupNested(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, iterations := range []int{1, 3, -1} {
+		for nPhases := 1; nPhases < 5; nPhases++ {
+			var phases []schedule.Phase[int]
+			var flat []actionInt
+			for p := 0; p < nPhases; p++ {
+				inner, _ := randomIntActions(rng, 0, 3, rng.Intn(3)+1)
+				inner[len(inner)-1].Duration++ // Ensure inner cycle has non-zero duration.
+				repeat := rng.Intn(4) + 1
+				phases = append(phases, schedule.Phase[int]{Inner: inner, Repeat: repeat})
+				for i := 0; i < repeat; i++ {
+					flat = append(flat, inner...)
+				}
+			}
+			gn, err := schedule.NewGroupNested(phases, schedule.GroupNestedConfig{Iterations: iterations})
+			if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+				t.Fatal(err)
+			}
+			gs, err := schedule.NewGroupSync(flat, schedule.GroupSyncConfig{Iterations: iterations})
+			if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+				t.Fatal(err)
+			}
+			if gn.Duration() != gs.Duration() {
+				t.Fatalf("got nested duration %d, want %d", gn.Duration(), gs.Duration())
+			}
+			var start time.Time
+			start = start.Add(1)
+			gn.Begin(start)
+			gs.Begin(start)
+			for elapsed := time.Duration(-1); elapsed <= 4*gs.Duration(); elapsed++ {
+				now := start.Add(elapsed)
+				for {
+					v1, ok1, next1, err1 := gn.ScheduleNext(now)
+					v2, ok2, next2, err2 := gs.ScheduleNext(now)
+					if v1 != v2 || ok1 != ok2 || next1 != next2 || (err1 == nil) != (err2 == nil) {
+						t.Fatalf("iterations=%d elapsed=%d: nested (%d,%v,%d,%v) != flattened (%d,%v,%d,%v)",
+							iterations, elapsed, v1, ok1, next1, err1, v2, ok2, next2, err2)
+					}
+					if !ok1 || next1 != 0 {
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	errBadCapacity      = errors.New("capacity must be at least 1")
+	errCapacityConflict = errors.New("tracks exceed resource capacity")
+)
+
+// maxCapacityEvents limits the action starts swept when checking capacities at construction.
+const maxCapacityEvents = 1 << 16
+
+// Track is an independent lane of actions of a GroupParallel.
+type Track[T any] struct {
+	Actions []Action[T]
+	// Iterations is the number of times the track runs, -1 for infinite iterations.
+	Iterations int
+}
+
+// Capacity limits how many tracks of a GroupParallel may run actions using a
+// shared resource at once, such as at most 2 heaters being on.
+type Capacity[T any] struct {
+	// Uses reports whether an action with value v uses the resource.
+	Uses func(v T) bool
+	// Max is the maximum number of tracks running actions that use the resource.
+	Max int
+}
+
+// GroupParallel runs several independent tracks of actions, such as a heater,
+// a fan and a LED, that share a single start time. Each track is scheduled
+// as a GroupSync with its own iteration count. GroupParallel is done once
+// all its tracks are done.
+type GroupParallel[T any] struct {
+	tracks     []*GroupSync[T]
+	done       []bool
+	capacities []Capacity[T]
+	// now is the time of the ScheduleNext call in progress, used by capacity guards.
+	now time.Time
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupParallel returns a group running tracks in parallel. cfg applies to
+// all tracks except for its Iterations which are specified per track.
+// Tracks share cfg.Recorder and their decisions are told apart by Decision.Track.
+//
+// Actions that would exceed one of capacities are guarded: they are handled
+// per cfg.OnGuard until enough tracks release the resource, i.e. deliver an
+// action that does not use it. An error is returned if the tracks exceed a
+// capacity when run on schedule. This is checked over the tracks' common period
+// up to a bounded number of actions.
+func NewGroupParallel[T any](tracks []Track[T], cfg GroupSyncConfig, capacities ...Capacity[T]) (*GroupParallel[T], error) {
+	if len(tracks) == 0 {
+		return nil, errEmptyActions
+	}
+	for _, c := range capacities {
+		if c.Max < 1 {
+			return nil, errBadCapacity
+		}
+	}
+	g := &GroupParallel[T]{
+		tracks:     make([]*GroupSync[T], len(tracks)),
+		done:       make([]bool, len(tracks)),
+		capacities: capacities,
+	}
+	var warning error
+	for i, track := range tracks {
+		cfg.Iterations = track.Iterations
+		gs, err := NewGroupSync(track.Actions, cfg)
+		if err != nil && !errors.Is(err, ErrSmallDuration) {
+			return nil, err
+		} else if err != nil {
+			warning = err
+		}
+		if len(capacities) > 0 {
+			gs.guard = g.capacityGuard(i, cfg.Guard)
+		}
+		gs.track = i
+		g.tracks[i] = gs
+	}
+	if err := g.checkCapacities(); err != nil {
+		return nil, err
+	}
+	return g, warning
+}
+
+// capacityGuard returns the guard of track i, which lets actions through if
+// guard does and starting them does not exceed a capacity. Other tracks use
+// the resource if the action they should be running at now per their timeline
+// uses it. Tracks held by their guard use the resource of their last action.
+func (g *GroupParallel[T]) capacityGuard(i int, guard func(index int) bool) func(index int) bool {
+	return func(index int) bool {
+		if guard != nil && !guard(index) {
+			return false
+		}
+		track := g.tracks[i]
+		v := track.actions[index].Value
+		for _, c := range g.capacities {
+			if !c.Uses(v) {
+				continue
+			}
+			users := 0
+			for j, other := range g.tracks {
+				if j == i || g.done[j] {
+					continue
+				}
+				if running, ok := other.runningAt(g.now); ok && c.Uses(running) {
+					users++
+				}
+			}
+			if users >= c.Max {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// runningAt returns the value of the action g is running at now.
+func (g *GroupSync[T]) runningAt(now time.Time) (v T, ok bool) {
+	if g.held {
+		if g.lastPos < 0 {
+			return v, false
+		}
+		return g.actions[g.lastPos%len(g.actions)].Value, true
+	}
+	elapsed, running := g.iterationElapsed(now)
+	if !running {
+		return v, false
+	}
+	idx, _ := g.currentIdx(elapsed)
+	if idx < 0 {
+		return v, false // Waiting for an anchored action.
+	}
+	return g.actions[idx].Value, true
+}
+
+// checkCapacities sweeps the action starts of all tracks run on schedule and
+// returns an error if the actions running at any of them exceed a capacity.
+func (g *GroupParallel[T]) checkCapacities() error {
+	if len(g.capacities) == 0 {
+		return nil
+	}
+	// Sweep the longest finite track and the common period of infinite tracks.
+	var horizon time.Duration
+	period := time.Duration(1)
+	for _, track := range g.tracks {
+		if track.iterations != -1 {
+			if d := time.Duration(track.iterations) * track.duration; d > horizon {
+				horizon = d // Overflow was checked by NewGroupSync.
+			}
+			continue
+		}
+		gcd := gcdDuration(period, track.duration)
+		if period/gcd > maxDuration/track.duration {
+			period = maxDuration
+		} else {
+			period = period / gcd * track.duration
+		}
+	}
+	if period > horizon {
+		horizon = period
+	}
+	var starts []time.Duration
+	for _, track := range g.tracks {
+		iterations := horizon / track.duration
+		if horizon%track.duration != 0 {
+			iterations++
+		}
+		if track.iterations != -1 && time.Duration(track.iterations) < iterations {
+			iterations = time.Duration(track.iterations)
+		}
+		for iter := time.Duration(0); iter < iterations && len(starts) < maxCapacityEvents; iter++ {
+			for _, offset := range track.offsets {
+				starts = append(starts, iter*track.duration+offset)
+			}
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	for _, at := range starts {
+		for _, c := range g.capacities {
+			users := 0
+			for _, track := range g.tracks {
+				if track.iterations != -1 && at >= time.Duration(track.iterations)*track.duration {
+					continue // Track done.
+				}
+				idx, _ := track.currentIdx(at % track.duration)
+				if c.Uses(track.actions[idx].Value) {
+					users++
+				}
+			}
+			if users > c.Max {
+				return fmt.Errorf("%w: %d tracks use a resource of capacity %d at %s", errCapacityConflict, users, c.Max, at)
+			}
+		}
+	}
+	return nil
+}
+
+// Begin sets the start time of all tracks. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupParallel[T]) Begin(start time.Time) {
+	g.lazy = start.IsZero()
+	for i, track := range g.tracks {
+		track.Begin(start)
+		g.done[i] = false
+	}
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupParallel[T]) StartTime() time.Time { return g.tracks[0].StartTime() }
+
+// Track returns the group scheduling track i.
+func (g *GroupParallel[T]) Track(i int) *GroupSync[T] { return g.tracks[i] }
+
+// ScheduleNext returns the value v of the next ready action of any track and
+// the index of its track when ok is true. Since other tracks may have actions
+// ready at the same time next is zero when ok is true and ScheduleNext should be
+// called again. When ok is false next is the time until an action of any track
+// is ready; if next is also zero all tracks are done. Errors are returned along
+// with the index of the track that caused them.
+func (g *GroupParallel[T]) ScheduleNext(now time.Time) (track int, v T, ok bool, next time.Duration, err error) {
+	if g.lazy {
+		g.lazy = false // Start all tracks at the same time.
+		for _, track := range g.tracks {
+			track.anchorLazy(now)
+		}
+	}
+	g.now = now
+	for i, gs := range g.tracks {
+		if g.done[i] {
+			continue
+		}
+		v, ok, nextTrack, err := gs.ScheduleNext(now)
+		switch {
+		case err != nil && !ok:
+			return i, v, false, 0, err
+		case ok:
+			return i, v, true, 0, err
+		case nextTrack == 0:
+			g.done[i] = true
+		case next == 0 || nextTrack < next:
+			next = nextTrack
+		}
+	}
+	return 0, v, false, next, nil
+}
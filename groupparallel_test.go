@@ -0,0 +1,128 @@
+package schedule_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupParallel(t *testing.T) {
+	tracks := []schedule.Track[string]{
+		{Actions: []schedule.Action[string]{{Duration: 2 * time.Second, Value: "heat"}, {Duration: 2 * time.Second, Value: "cool"}}, Iterations: 1},
+		{Actions: []schedule.Action[string]{{Duration: 3 * time.Second, Value: "blink"}}, Iterations: 2},
+	}
+	rec := schedule.NewRecorder(64)
+	g, err := schedule.NewGroupParallel(tracks, schedule.GroupSyncConfig{Recorder: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	type fired struct {
+		track int
+		v     string
+	}
+	var got []fired
+	now := start
+	for {
+		track, v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			got = append(got, fired{track, v})
+			continue
+		}
+		if next == 0 {
+			break
+		}
+		now = now.Add(next)
+	}
+	want := []fired{{0, "heat"}, {1, "blink"}, {0, "cool"}, {1, "blink"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+	if end := now.Sub(start); end != 6*time.Second {
+		t.Errorf("got group end at %s, want longest track end", end)
+	}
+	var recorded []fired
+	for _, d := range rec.Decisions() {
+		if d.Ok {
+			recorded = append(recorded, fired{d.Track, d.Value})
+		}
+	}
+	if fmt.Sprint(recorded) != fmt.Sprint(want) {
+		t.Errorf("got recorded %v, want %v", recorded, want)
+	}
+}
+
+func TestGroupParallelCapacity(t *testing.T) {
+	on := func(v string) bool { return v == "on" }
+	heater := []schedule.Action[string]{{Duration: 2 * time.Second, Value: "on"}, {Duration: 2 * time.Second, Value: "off"}}
+	shifted := []schedule.Action[string]{{Duration: 2 * time.Second, Value: "off"}, {Duration: 2 * time.Second, Value: "on"}}
+	overlap := []schedule.Action[string]{{Duration: time.Second, Value: "off"}, {Duration: 2 * time.Second, Value: "on"}, {Duration: time.Second, Value: "off"}}
+	tracks := []schedule.Track[string]{{Actions: heater, Iterations: -1}, {Actions: heater, Iterations: -1}, {Actions: overlap, Iterations: 1}}
+	capacity := schedule.Capacity[string]{Uses: on, Max: 2}
+	if _, err := schedule.NewGroupParallel(tracks, schedule.GroupSyncConfig{}, capacity); err == nil {
+		t.Error("expected error for three heaters on at once")
+	}
+	if _, err := schedule.NewGroupParallel(tracks[:2], schedule.GroupSyncConfig{}, capacity); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resources are released on schedule regardless of the order tracks are polled in.
+	tracks = []schedule.Track[string]{{Actions: shifted, Iterations: 1}, {Actions: heater, Iterations: 1}}
+	single := schedule.Capacity[string]{Uses: on, Max: 1}
+	g, err := schedule.NewGroupParallel(tracks, schedule.GroupSyncConfig{}, single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	want := "[0s:0:off 0s:1:on 2s:0:on 2s:1:off]"
+	if got := parallelTrace(t, g, start, 0, 2*time.Second); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// An action is held while a delayed track still uses the resource.
+	delayed := []schedule.Action[string]{{Duration: time.Second, Value: "on"}, {Duration: time.Second, Value: "on2"}, {Duration: 2 * time.Second, Value: "off"}}
+	tracks = []schedule.Track[string]{{Actions: shifted, Iterations: 1}, {Actions: delayed, Iterations: 1}}
+	single.Uses = func(v string) bool { return strings.HasPrefix(v, "on") }
+	g, err = schedule.NewGroupParallel(tracks, schedule.GroupSyncConfig{OnGuard: schedule.GuardHold, CompleteLate: true}, single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "[0s:0:off 0s:1:on 1.5s:1:on2 2.5s:0:on 2.5s:1:off]"
+	if got := parallelTrace(t, g, start, 0, 1500*time.Millisecond, 2*time.Second, 2500*time.Millisecond); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// parallelTrace begins g at start and polls it at each of elapsed until no
+// action is ready, returning the actions scheduled.
+func parallelTrace(t *testing.T, g *schedule.GroupParallel[string], start time.Time, elapsed ...time.Duration) string {
+	t.Helper()
+	g.Begin(start)
+	var got []string
+	for _, e := range elapsed {
+		for {
+			track, v, ok, _, err := g.ScheduleNext(start.Add(e))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			got = append(got, fmt.Sprintf("%s:%d:%s", e, track, v))
+		}
+	}
+	return fmt.Sprint(got)
+}
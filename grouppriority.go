@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+// GroupPriority runs lanes of actions with different priorities on a single
+// timeline, such as alarms overriding a routine sequence. The lowest priority
+// lane is the base schedule started by Begin. Higher priority lanes are started
+// on demand with Raise and preempt lower priority lanes while they run, which
+// keep their phase meanwhile. Once a lane is done the highest priority lane still
+// running resumes with its current action. With the ResumePaused policy preempted
+// lanes are paused instead and their preempted action resumes for its remaining
+// duration, delaying the rest of the lane. Lanes are scheduled as GroupSync.
+type GroupPriority[T any] struct {
+	// lanes are sorted by decreasing priority.
+	lanes []*GroupSync[T]
+	// pausedAt holds the time each lane was preempted at when lanes are paused
+	// while preempted, zero if the lane is not paused.
+	pausedAt []time.Time
+	// active is the lane of the last returned action, -1 if none.
+	active    int
+	preempted bool
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupPriority returns a group running lanes sorted by decreasing priority,
+// the last lane being the base schedule. cfg applies to all lanes except for
+// its Iterations which are specified per lane. cfg.Resume selects whether
+// preempted lanes keep their phase, the default, or are paused.
+func NewGroupPriority[T any](lanes []Track[T], cfg GroupSyncConfig) (*GroupPriority[T], error) {
+	if len(lanes) == 0 {
+		return nil, errEmptyActions
+	}
+	g := &GroupPriority[T]{
+		lanes:    make([]*GroupSync[T], len(lanes)),
+		pausedAt: make([]time.Time, len(lanes)),
+		active:   -1,
+	}
+	var warning error
+	for i, lane := range lanes {
+		cfg.Iterations = lane.Iterations
+		gs, err := NewGroupSync(lane.Actions, cfg)
+		if err != nil && !errors.Is(err, ErrSmallDuration) {
+			return nil, err
+		} else if err != nil {
+			warning = err
+		}
+		g.lanes[i] = gs
+	}
+	return g, warning
+}
+
+// Begin starts the base schedule, the lowest priority lane, and stops all other
+// lanes. It must be called before ScheduleNext. A zero start time anchors the
+// start to the time passed to the first ScheduleNext call.
+func (g *GroupPriority[T]) Begin(start time.Time) {
+	g.lazy = start.IsZero()
+	for _, lane := range g.lanes[:len(g.lanes)-1] {
+		lane.start, lane.lazy = time.Time{}, false // Stop lane until raised.
+	}
+	g.lanes[len(g.lanes)-1].Begin(start)
+	for i := range g.pausedAt {
+		g.pausedAt[i] = time.Time{}
+	}
+	g.active = -1
+	g.preempted = false
+}
+
+// Raise starts the lane at index lane at time at, preempting lower priority
+// lanes while it runs. Raising a running lane restarts it. A zero time starts
+// the lane at the time passed to the next ScheduleNext call.
+func (g *GroupPriority[T]) Raise(lane int, at time.Time) {
+	g.lanes[lane].Begin(at)
+	g.pausedAt[lane] = time.Time{}
+}
+
+// StartTime returns the start time of the base schedule. If not started returns zero value.
+func (g *GroupPriority[T]) StartTime() time.Time { return g.lanes[len(g.lanes)-1].StartTime() }
+
+// Lane returns the index of the lane of the action returned by the last successful
+// call to ScheduleNext, or -1 if no lane is running.
+func (g *GroupPriority[T]) Lane() int { return g.active }
+
+// Preempted reports whether the action returned by the last successful call to
+// ScheduleNext cut short the running action of a lower priority lane.
+func (g *GroupPriority[T]) Preempted() bool { return g.preempted }
+
+// ScheduleNext returns the value of the highest priority running lane when ok is
+// true, either because the lane scheduled a new action or because the lane
+// preempted or resumed another lane. See Lane and Preempted. Actions of
+// preempted lanes are not returned. If ok is false and next is zero all lanes are done.
+func (g *GroupPriority[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy {
+		g.lazy = false
+		g.lanes[len(g.lanes)-1].anchorLazy(now)
+	}
+	if g.lanes[len(g.lanes)-1].start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	top := -1
+	var topNext time.Duration
+	for i, lane := range g.lanes {
+		lane.anchorLazy(now) // Lane raised with a zero time.
+		if lane.start.IsZero() {
+			continue // Not raised.
+		}
+		if lane.resume == ResumePaused && lane.lastPos >= 0 {
+			if top != -1 {
+				if g.pausedAt[i].IsZero() {
+					g.pausedAt[i] = now
+				}
+				continue // Paused while preempted.
+			}
+			if !g.pausedAt[i].IsZero() {
+				// Delay the lane so the preempted action runs for its remaining duration.
+				lane.elapsedToRestart += now.Sub(g.pausedAt[i])
+				g.pausedAt[i] = time.Time{}
+			}
+		}
+		laneV, laneOk, laneNext, laneErr := lane.ScheduleNext(now)
+		switch {
+		case laneErr != nil && !laneOk:
+			return v, false, 0, laneErr
+		case !laneOk && laneNext == 0:
+			continue // Lane done.
+		case laneNext > 0 && (next == 0 || laneNext < next):
+			next = laneNext
+		}
+		if top != -1 || now.Before(lane.start) || lane.lastPos < 0 {
+			continue
+		}
+		top, topNext = i, laneNext
+		if laneOk {
+			v, ok, err = laneV, true, laneErr
+		} else {
+			v = lane.actions[lane.lastPos%len(lane.actions)].Value
+		}
+	}
+	prev := g.active
+	g.active = top
+	if top == -1 || (!ok && top == prev) {
+		var zero T
+		return zero, false, next, nil
+	}
+	// Lanes preempt lanes of lower priority, which have higher indices.
+	g.preempted = prev != -1 && top < prev
+	if topNext == 0 {
+		next = 0 // Zero duration action, call again.
+	}
+	return v, true, next, err
+}
@@ -0,0 +1,98 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupPriority(t *testing.T) {
+	lanes := []schedule.Track[string]{
+		{Actions: []schedule.Action[string]{{Duration: 1500 * time.Millisecond, Value: "alarm"}}, Iterations: 1},
+		{Actions: []schedule.Action[string]{{Duration: time.Second, Value: "a"}, {Duration: time.Second, Value: "b"}}, Iterations: -1},
+	}
+	g, err := schedule.NewGroupPriority(lanes, schedule.GroupSyncConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.Raise(0, start.Add(500*time.Millisecond))
+	tests := []struct {
+		at        time.Duration
+		ok        bool
+		want      string
+		preempted bool
+		next      time.Duration
+	}{
+		{at: 0, ok: true, want: "a", next: 500 * time.Millisecond},
+		{at: 500 * time.Millisecond, ok: true, want: "alarm", preempted: true, next: 500 * time.Millisecond},
+		{at: 1000 * time.Millisecond, next: time.Second}, // Routine action shadowed by alarm.
+		{at: 2000 * time.Millisecond, ok: true, want: "a", next: time.Second},
+		{at: 3000 * time.Millisecond, ok: true, want: "b", next: time.Second},
+	}
+	for _, test := range tests {
+		v, ok, next, err := g.ScheduleNext(start.Add(test.at))
+		if err != nil || ok != test.ok || v != test.want || next != test.next {
+			t.Errorf("at %s got (%q, %t, %s, %v), want (%q, %t, %s)", test.at, v, ok, next, err, test.want, test.ok, test.next)
+		}
+		if ok && g.Preempted() != test.preempted {
+			t.Errorf("at %s got preempted=%t", test.at, g.Preempted())
+		}
+	}
+}
+
+func TestGroupPriorityRaiseLazy(t *testing.T) {
+	lanes := []schedule.Track[string]{
+		{Actions: []schedule.Action[string]{{Duration: time.Second, Value: "alarm"}}, Iterations: 1},
+		{Actions: []schedule.Action[string]{{Duration: 10 * time.Second, Value: "a"}}, Iterations: 1},
+	}
+	g, err := schedule.NewGroupPriority(lanes, schedule.GroupSyncConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	g.Raise(0, time.Time{})
+	v, ok, next, err := g.ScheduleNext(start.Add(3 * time.Second))
+	if err != nil || !ok || v != "alarm" || next != time.Second || !g.Preempted() {
+		t.Errorf("got (%q, %t, %s, %v), want alarm raised at the next call", v, ok, next, err)
+	}
+	if v, ok, _, _ = g.ScheduleNext(start.Add(4 * time.Second)); !ok || v != "a" {
+		t.Errorf("got (%q, %t), want base lane resumed after alarm", v, ok)
+	}
+}
+
+func TestGroupPriorityResumePaused(t *testing.T) {
+	lanes := []schedule.Track[string]{
+		{Actions: []schedule.Action[string]{{Duration: 3 * time.Second, Value: "alarm"}}, Iterations: 1},
+		{Actions: []schedule.Action[string]{{Duration: 10 * time.Second, Value: "a"}, {Duration: 10 * time.Second, Value: "b"}}, Iterations: 1},
+	}
+	g, err := schedule.NewGroupPriority(lanes, schedule.GroupSyncConfig{Resume: schedule.ResumePaused})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.Raise(0, start.Add(4*time.Second))
+	tests := []struct {
+		at   time.Duration
+		ok   bool
+		want string
+		next time.Duration
+	}{
+		{at: 0, ok: true, want: "a", next: 4 * time.Second},
+		{at: 4 * time.Second, ok: true, want: "alarm", next: 3 * time.Second},
+		{at: 7 * time.Second, ok: true, want: "a", next: 6 * time.Second}, // Remaining duration of a.
+		{at: 13 * time.Second, ok: true, want: "b", next: 10 * time.Second},
+		{at: 23 * time.Second},
+	}
+	for _, test := range tests {
+		v, ok, next, err := g.ScheduleNext(start.Add(test.at))
+		if err != nil || ok != test.ok || v != test.want || next != test.next {
+			t.Errorf("at %s got (%q, %t, %s, %v), want (%q, %t, %s)", test.at, v, ok, next, err, test.want, test.ok, test.next)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+var errBadWeights = errors.New("weights must be non-negative, one per action and not all zero")
+
+type GroupWeightedRandomConfig struct {
+	// Weights are the relative probabilities of choosing each action. Must have
+	// one non-negative weight per action. Nil weights choose actions uniformly.
+	Weights []float64
+	// Seed seeds the random number generator so sequences are reproducible.
+	// The sequence restarts every time Begin is called.
+	Seed int64
+	// Steps is the number of actions to schedule before the group is done.
+	// Must be greater than zero or -1 to indicate infinite steps.
+	Steps int
+}
+
+// GroupWeightedRandom schedules actions chosen at random, such as randomized
+// stimuli for a hardware test rig. Each chosen action runs for its full duration
+// before the next action is chosen, with the timing semantics of GroupLoose.
+type GroupWeightedRandom[T any] struct {
+	actions []Action[T]
+	// cumulative holds the cumulative sum of the weights of the actions.
+	cumulative []float64
+	rng        *rand.Rand
+	seed       int64
+	steps      int
+	start      time.Time
+	// lastStart is the time the last action was scheduled at.
+	lastStart time.Time
+	lastIdx   int
+	// scheduled is the number of actions scheduled since Begin.
+	scheduled int
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupWeightedRandom returns a group that chooses each action at random
+// with probability proportional to its weight.
+func NewGroupWeightedRandom[T any](actions []Action[T], cfg GroupWeightedRandomConfig) (*GroupWeightedRandom[T], error) {
+	_, err := actionsDuration(actions)
+	switch {
+	case err != nil && !errors.Is(err, ErrSmallDuration):
+		return nil, err
+	case len(actions) == 0:
+		return nil, errEmptyActions
+	case cfg.Steps <= 0 && cfg.Steps != -1:
+		return nil, errBadIterations
+	case cfg.Weights != nil && len(cfg.Weights) != len(actions):
+		return nil, errBadWeights
+	}
+	cumulative := make([]float64, len(actions))
+	var total float64
+	for i := range actions {
+		weight := 1.0
+		if cfg.Weights != nil {
+			weight = cfg.Weights[i]
+		}
+		if weight < 0 {
+			return nil, errBadWeights
+		}
+		total += weight
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, errBadWeights
+	}
+	return &GroupWeightedRandom[T]{
+		actions:    actions,
+		cumulative: cumulative,
+		rng:        rand.New(rand.NewSource(cfg.Seed)),
+		seed:       cfg.Seed,
+		steps:      cfg.Steps,
+	}, nil
+}
+
+// Begin sets the start time of the group and reseeds its random number generator.
+// It must be called before ScheduleNext. A zero start time anchors the start
+// to the time passed to the first ScheduleNext call.
+func (g *GroupWeightedRandom[T]) Begin(start time.Time) {
+	g.start = start
+	g.lazy = start.IsZero()
+	g.lastStart = time.Time{}
+	g.lastIdx = -1
+	g.scheduled = 0
+	g.rng.Seed(g.seed)
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupWeightedRandom[T]) StartTime() time.Time {
+	return g.start
+}
+
+// Steps returns the number of actions the group schedules. It may be -1 for infinite steps.
+func (g *GroupWeightedRandom[T]) Steps() int {
+	return g.steps
+}
+
+// LastIndex returns the index of the last scheduled action or -1 if no action
+// was scheduled since Begin.
+func (g *GroupWeightedRandom[T]) LastIndex() int {
+	return g.lastIdx
+}
+
+// ScheduleNext works like GroupLoose.ScheduleNext, choosing the next action at
+// random once the last scheduled action's duration elapses.
+func (g *GroupWeightedRandom[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy && g.start.IsZero() {
+		g.Begin(now)
+	}
+	if g.start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	if elapsed := now.Sub(g.start); elapsed < 0 {
+		return v, false, -elapsed, nil // Still waiting for start time.
+	}
+	if g.lastIdx >= 0 {
+		if remaining := g.actions[g.lastIdx].Duration - now.Sub(g.lastStart); remaining > 0 {
+			return v, false, remaining, nil // Still waiting for next action.
+		}
+	}
+	if g.steps != -1 && g.scheduled >= g.steps {
+		return v, false, 0, nil // Done.
+	}
+	r := g.rng.Float64() * g.cumulative[len(g.cumulative)-1]
+	g.lastIdx = sort.Search(len(g.cumulative), func(i int) bool { return g.cumulative[i] > r })
+	g.lastStart = now
+	g.scheduled++
+	action := g.actions[g.lastIdx]
+	return action.Value, true, action.Duration, nil
+}
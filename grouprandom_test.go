@@ -0,0 +1,59 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupWeightedRandom(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 0}, {Duration: 2 * time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	const steps = 3000
+	g, err := schedule.NewGroupWeightedRandom(actions, schedule.GroupWeightedRandomConfig{
+		Weights: []float64{1, 2, 0},
+		Seed:    1,
+		Steps:   steps,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func() (seq []int) {
+		start := time.Unix(100, 0)
+		g.Begin(start)
+		now := start
+		for {
+			v, ok, next, err := g.ScheduleNext(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok && next == 0 {
+				return seq
+			}
+			if ok {
+				seq = append(seq, v)
+				if next != actions[v].Duration {
+					t.Fatalf("got next %s for action %d, want its duration", next, v)
+				}
+			}
+			now = now.Add(next)
+		}
+	}
+	seq := run()
+	var counts [3]int
+	for _, v := range seq {
+		counts[v]++
+	}
+	if len(seq) != steps || counts[2] != 0 || counts[1] < steps/2 || counts[1] > steps*3/4 {
+		t.Errorf("got %d steps with counts %v, want about twice as many 1 as 0 and no 2", len(seq), counts)
+	}
+	again := run()
+	for i := range seq {
+		if seq[i] != again[i] {
+			t.Fatal("sequence not reproducible after Begin")
+		}
+	}
+	if _, err = schedule.NewGroupWeightedRandom(actions, schedule.GroupWeightedRandomConfig{Weights: []float64{0, 0, 0}, Steps: 1}); err == nil {
+		t.Error("expected error for zero weights")
+	}
+}
@@ -0,0 +1,121 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+var errSelectDuration = errors.New("selectable schedules must have equal duration")
+
+// GroupSelect runs one of two alternative schedules on each iteration, such as
+// eco and comfort heating profiles, on a single coherent timeline. A user
+// predicate chooses the schedule at each iteration boundary. Both schedules
+// must have the same duration so the group's phase does not depend on the
+// choices made. GroupSelect shares the miss semantics of GroupSync.
+type GroupSelect[T any] struct {
+	primary, alternative *GroupSync[T]
+	useAlternative       func(iteration int) bool
+	current              *GroupSync[T]
+	// iter is the iteration the current schedule was chosen for.
+	iter int
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupSelect returns a group that runs alternative on the iterations
+// for which useAlternative returns true and primary otherwise.
+// useAlternative is called once per iteration, when the iteration is first polled.
+func NewGroupSelect[T any](primary, alternative []Action[T], useAlternative func(iteration int) bool, cfg GroupSyncConfig) (*GroupSelect[T], error) {
+	if hasAnchors(primary) || hasAnchors(alternative) {
+		return nil, errAnchorIterations
+	}
+	p, err := NewGroupSync(primary, cfg)
+	if err != nil && !errors.Is(err, ErrSmallDuration) {
+		return nil, err
+	}
+	a, err2 := NewGroupSync(alternative, cfg)
+	if err2 != nil && !errors.Is(err2, ErrSmallDuration) {
+		return nil, err2
+	}
+	if p.Duration() != a.Duration() {
+		return nil, errSelectDuration
+	}
+	if err == nil {
+		err = err2
+	}
+	return &GroupSelect[T]{primary: p, alternative: a, useAlternative: useAlternative, current: p, iter: -1}, err
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupSelect[T]) Begin(start time.Time) {
+	g.lazy = start.IsZero()
+	g.primary.Begin(start)
+	g.alternative.Begin(start)
+	g.current = g.primary
+	g.iter = -1
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupSelect[T]) StartTime() time.Time { return g.primary.StartTime() }
+
+// Duration returns the duration of a single iteration.
+func (g *GroupSelect[T]) Duration() time.Duration { return g.primary.Duration() }
+
+// Iterations returns the number of iterations the group will run for.
+// It may be -1 for infinite iterations.
+func (g *GroupSelect[T]) Iterations() int { return g.primary.Iterations() }
+
+// Alternative reports whether the alternative schedule was chosen for the
+// iteration of the last ScheduleNext call.
+func (g *GroupSelect[T]) Alternative() bool { return g.current == g.alternative }
+
+// ScheduleNext works like GroupSync.ScheduleNext, scheduling the actions of
+// the schedule chosen for the iteration running at now.
+func (g *GroupSelect[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy {
+		g.lazy = false // Start both schedules at the same time.
+		g.primary.anchorLazy(now)
+		g.alternative.anchorLazy(now)
+	}
+	start := g.current.StartTime()
+	if !start.IsZero() && !now.Before(start) {
+		iter := g.current.iterationAt(now)
+		if iter > g.iter && (g.Iterations() == -1 || iter < g.Iterations()) {
+			if err = g.choose(iter); err != nil {
+				return v, false, 0, err
+			}
+		}
+	}
+	return g.current.ScheduleNext(now)
+}
+
+// choose selects the schedule for iteration iter. A newly chosen schedule
+// continues from the start of iter on the timeline of the previous schedule,
+// so shifts such as late completions and holds carry over.
+func (g *GroupSelect[T]) choose(iter int) error {
+	prev := g.current
+	g.iter = iter
+	g.current = g.primary
+	if g.useAlternative(iter) {
+		g.current = g.alternative
+	}
+	if g.current == prev {
+		return nil // Schedule handles its own missed actions.
+	}
+	switch {
+	case prev.failed:
+		g.current.failed = true
+		return ErrGroupFailed
+	case prev.lastPos < iter*len(prev.actions)-1 && prev.onMiss == MissFail:
+		// Actions of previous iteration were not scheduled.
+		g.current.failed = true
+		return prev.actionError(prev.lastPos+1, ErrMissedAction)
+	}
+	g.current.start = prev.start
+	g.current.elapsedToRestart = prev.elapsedToRestart
+	g.current.restartIter = prev.restartIter
+	g.current.lastPos = iter*len(g.current.actions) - 1
+	return nil
+}
@@ -0,0 +1,86 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupSelect(t *testing.T) {
+	eco := []actionInt{{Duration: 2 * time.Second, Value: 1}, {Duration: 2 * time.Second, Value: 2}}
+	comfort := []actionInt{{Duration: time.Second, Value: 10}, {Duration: 2 * time.Second, Value: 20}, {Duration: time.Second, Value: 30}}
+	var chosen []int
+	g, err := schedule.NewGroupSelect(eco, comfort, func(iter int) bool {
+		chosen = append(chosen, iter)
+		return iter%2 == 1
+	}, schedule.GroupSyncConfig{Iterations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	var got []int
+	for now := start; ; now = now.Add(500 * time.Millisecond) {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok && next == 0 {
+			break
+		}
+		if ok {
+			got = append(got, v)
+		}
+	}
+	want := []int{1, 2, 10, 20, 30, 1, 2}
+	if len(got) != len(want) || len(chosen) != 3 {
+		t.Fatalf("got values %v with choices for iterations %v, want %v", got, chosen, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("action %d: got %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	// Missing the end of an iteration fails the group when switching schedules.
+	g.Begin(start)
+	g.ScheduleNext(start)
+	_, _, _, err = g.ScheduleNext(start.Add(5 * time.Second))
+	var actionErr *schedule.ActionError
+	if !errors.As(err, &actionErr) || actionErr.Index != 1 || actionErr.Iteration != 0 {
+		t.Errorf("got %v, want error locating missed action", err)
+	}
+	if !errors.Is(err, schedule.ErrMissedAction) {
+		t.Error("action error does not wrap ErrMissedAction")
+	}
+
+	// Iterations follow the timeline of the running schedule when it is shifted.
+	ms := time.Millisecond
+	primary := []actionInt{{Duration: 10 * ms, Value: 1}, {Duration: 10 * ms, Value: 2}}
+	alternative := []actionInt{{Duration: 10 * ms, Value: 3}, {Duration: 10 * ms, Value: 4}}
+	g, err = schedule.NewGroupSelect(primary, alternative, func(iter int) bool {
+		return iter%2 == 1
+	}, schedule.GroupSyncConfig{Iterations: 2, CompleteLate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	for _, tc := range []struct {
+		elapsed time.Duration
+		ok      bool
+		v       int
+	}{{0, true, 1}, {15, true, 2}, {22, false, 0}, {25, true, 3}, {35, true, 4}} {
+		v, ok, _, err := g.ScheduleNext(start.Add(tc.elapsed * ms))
+		if err != nil {
+			t.Fatalf("elapsed=%d: %v", tc.elapsed, err)
+		}
+		if ok != tc.ok || v != tc.v {
+			t.Errorf("elapsed=%d: got ok=%v v=%d, want ok=%v v=%d", tc.elapsed, ok, v, tc.ok, tc.v)
+		}
+	}
+	if _, err = schedule.NewGroupSelect(eco, comfort[:2], nil, schedule.GroupSyncConfig{Iterations: 1}); err == nil {
+		t.Error("expected error for schedules of different duration")
+	}
+}
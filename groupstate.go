@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+var errBadTransition = errors.New("transition to state that is not a successor")
+
+// State is an action of a GroupStateMachine along with the indices of the
+// states that may follow it. A state without successors is terminal.
+type State[T any] struct {
+	Action[T]
+	Successors []int
+}
+
+// GroupStateMachine schedules actions driven by a transition table, such as
+// a traffic light serving pedestrian requests. Scheduling starts at the first
+// state. When a state's duration elapses a user callback chooses the next state
+// among its successors. The group is done once a terminal state's duration
+// elapses. GroupStateMachine has the timing semantics of GroupLoose.
+type GroupStateMachine[T any] struct {
+	states []State[T]
+	choose func(state int, successors []int) int
+	start  time.Time
+	// lastStart is the time the current state was scheduled at.
+	lastStart time.Time
+	current   int
+	failed    bool
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
+}
+
+// NewGroupStateMachine returns a state machine group. choose is called with the
+// index of the state whose duration elapsed and its successors and must return
+// one of the successors. If choose is nil the first successor is chosen.
+func NewGroupStateMachine[T any](states []State[T], choose func(state int, successors []int) int) (*GroupStateMachine[T], error) {
+	if len(states) == 0 {
+		return nil, errEmptyActions
+	}
+	for _, s := range states {
+		if s.Duration < 0 {
+			return nil, errNegativeDuration
+		}
+		for _, next := range s.Successors {
+			if next < 0 || next >= len(states) {
+				return nil, errBadTransition
+			}
+		}
+	}
+	return &GroupStateMachine[T]{states: states, choose: choose, current: -1}, nil
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call.
+func (g *GroupStateMachine[T]) Begin(start time.Time) {
+	g.start = start
+	g.lazy = start.IsZero()
+	g.lastStart = time.Time{}
+	g.current = -1
+	g.failed = false
+}
+
+// StartTime returns the time the group was started at. If not started returns zero value.
+func (g *GroupStateMachine[T]) StartTime() time.Time {
+	return g.start
+}
+
+// State returns the index of the current state or -1 if no state was scheduled since Begin.
+func (g *GroupStateMachine[T]) State() int {
+	return g.current
+}
+
+// ScheduleNext works like GroupLoose.ScheduleNext, returning the value of the
+// state chosen once the current state's duration elapses. If choose returns a
+// state that is not a successor the group fails.
+func (g *GroupStateMachine[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.lazy && g.start.IsZero() {
+		g.Begin(now)
+	}
+	if g.start.IsZero() {
+		return v, false, 0, ErrBeginNotCalled
+	}
+	if g.failed {
+		return v, false, 0, ErrGroupFailed
+	}
+	if elapsed := now.Sub(g.start); elapsed < 0 {
+		return v, false, -elapsed, nil // Still waiting for start time.
+	}
+	nextState := 0
+	if g.current >= 0 {
+		s := g.states[g.current]
+		if remaining := s.Duration - now.Sub(g.lastStart); remaining > 0 {
+			return v, false, remaining, nil // Still waiting for transition.
+		}
+		if len(s.Successors) == 0 {
+			return v, false, 0, nil // Done, terminal state.
+		}
+		nextState = s.Successors[0]
+		if g.choose != nil {
+			nextState = g.choose(g.current, s.Successors)
+		}
+		if !contains(s.Successors, nextState) {
+			g.failed = true
+			return v, false, 0, errBadTransition
+		}
+	}
+	g.current = nextState
+	g.lastStart = now
+	s := g.states[nextState]
+	return s.Value, true, s.Duration, nil
+}
+
+func contains(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
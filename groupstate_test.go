@@ -0,0 +1,77 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestGroupStateMachine(t *testing.T) {
+	const (
+		green = iota
+		yellow
+		red
+		walk
+	)
+	states := []schedule.State[string]{
+		green:  {Action: schedule.Action[string]{Duration: 10 * time.Second, Value: "green"}, Successors: []int{yellow}},
+		yellow: {Action: schedule.Action[string]{Duration: 2 * time.Second, Value: "yellow"}, Successors: []int{red}},
+		red:    {Action: schedule.Action[string]{Duration: 5 * time.Second, Value: "red"}, Successors: []int{green, walk}},
+		walk:   {Action: schedule.Action[string]{Duration: 8 * time.Second, Value: "walk"}},
+	}
+	pedestrian := false
+	g, err := schedule.NewGroupStateMachine(states, func(state int, successors []int) int {
+		if state == red && pedestrian {
+			return walk
+		}
+		return successors[0]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	var got []string
+	now := start
+	for {
+		if now.Sub(start) >= 20*time.Second {
+			pedestrian = true
+		}
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok && next == 0 {
+			break
+		}
+		if ok {
+			got = append(got, v)
+		}
+		now = now.Add(next)
+	}
+	want := []string{"green", "yellow", "red", "green", "yellow", "red", "walk"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if end := now.Sub(start); end != 42*time.Second {
+		t.Errorf("got end %s, want 42s", end)
+	}
+
+	// Choosing a state that is not a successor fails the group.
+	g, _ = schedule.NewGroupStateMachine(states, func(int, []int) int { return walk })
+	g.Begin(start)
+	g.ScheduleNext(start)
+	if _, _, _, err = g.ScheduleNext(start.Add(10 * time.Second)); err == nil {
+		t.Error("expected error for invalid transition")
+	}
+	if _, _, _, err = g.ScheduleNext(start.Add(11 * time.Second)); !errors.Is(err, schedule.ErrGroupFailed) {
+		t.Errorf("got %v, want ErrGroupFailed", err)
+	}
+}
@@ -3,47 +3,243 @@ package schedule
 import (
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 )
 
 // Common errors.
 var (
-	errBeginNotCalled   = errors.New("ScheduleNext called before Begin")
-	errMissedAction     = errors.New("missed action. This happens if event loop Update is not called at enough high frequency to prevent missing an action between calls")
-	errGroupFailed      = errors.New("group failed")
 	ErrSmallDuration    = errors.New("small duration. This may cause missed action errors")
-	errZeroDuration     = errors.New("zero duration in GroupSync. Use GroupLoose for when actions can have zero duration")
+	errZeroDuration     = errors.New("zero total duration in GroupSync. Use GroupLoose for when all actions have zero duration")
 	errBadIterations    = errors.New("zero or negative iterations")
 	errNegativeDuration = errors.New("negative action duration")
 	errEmptyActions     = errors.New("empty actions")
+	errTooLate          = fmt.Errorf("%w: action triggered later than maximum lateness allowed", ErrMissedAction)
+	errBadTickPeriod    = errors.New("zero or negative tick period")
+	errAnchorIterations = errors.New("actions anchored to absolute time require a single iteration")
+	errAnchorOrder      = errors.New("anchored action starts before preceding actions end")
+	errAnchorStart      = errors.New("group started too late for anchored actions")
+	errHarmonicPeriod   = errors.New("harmonic group period must be an exact divisor of master period")
+	errBadMissPolicy    = errors.New("invalid miss policy")
+	errNoHorizon        = errors.New("simulating infinite group requires a horizon")
+	errBadDeadLetters   = errors.New("negative maximum dead letters")
+	errBadInjectPolicy  = errors.New("invalid inject policy")
+	errBadResumePolicy  = errors.New("invalid resume policy")
+	errBadResolution    = errors.New("action durations must be multiples of resolution")
+	errOffsetOrder      = errors.New("action offsets must not decrease and must be within period")
+	errBadGuardPolicy   = errors.New("invalid guard policy")
+	// ErrDurationOverflow is returned when the total scheduled time of a group
+	// exceeds the maximum time.Duration.
+	ErrDurationOverflow = errors.New("total scheduled duration overflows time.Duration")
+	// ErrUnderPolling is a warning returned alongside valid ScheduleNext results
+	// when the interval between calls exceeds the group's RequiredResolution.
+	ErrUnderPolling = errors.New("under-polling: interval between ScheduleNext calls may cause missed actions")
+	// ErrBeginNotCalled is returned by all groups when ScheduleNext is called
+	// before the group's start time is set with Begin.
+	ErrBeginNotCalled = errors.New("ScheduleNext called before Begin")
+	// ErrMissedAction is returned, possibly wrapped, when an action is missed or
+	// triggered too late. Groups fail on this error unless configured otherwise.
+	ErrMissedAction = errors.New("missed action. This happens if ScheduleNext is not called at enough high frequency to prevent missing an action between calls")
+	// ErrGroupFailed is returned by ScheduleNext after a group failed until
+	// Begin is called again.
+	ErrGroupFailed = errors.New("group failed")
 )
 
 type GroupSyncConfig struct {
 	// Iterations specifies how many times to run the group. Must be greater than zero
 	// or -1 to indicate infinite iterations.
 	Iterations int
+	// StopOnBoundary makes StopAt and StopAfter let the action running at the
+	// stop time complete before the group reports done. By default the group
+	// reports done as soon as the stop time is reached.
+	StopOnBoundary bool
+	// DetectUnderPolling enables tracking of the interval between ScheduleNext calls.
+	// When the interval exceeds both RequiredResolution and the next returned by the
+	// previous call ScheduleNext returns an error wrapping ErrUnderPolling alongside
+	// otherwise valid results. The group does not fail.
+	DetectUnderPolling bool
+	// Reanchor makes each iteration start at the time its first action was actually
+	// scheduled instead of the time it was due. Lateness of the first action then
+	// delays the rest of the group instead of accumulating over many iterations.
+	Reanchor bool
+	// CompleteLate makes actions scheduled late run for their full duration, delaying
+	// the rest of the group by the lateness, instead of being truncated to preserve
+	// the group's periodicity. Unlike GroupLoose missed actions are still detected.
+	CompleteLate bool
+	// MaxPulseCorrection bounds the phase correction applied by each call to SyncPulse.
+	// Zero value means the phase is corrected fully on every pulse. To prevent
+	// missed actions it should be smaller than the shortest action duration.
+	MaxPulseCorrection time.Duration
+	// OnMiss specifies how actions not scheduled during their allotted time are handled.
+	// The default is MissFail.
+	OnMiss MissPolicy
+	// Discipline optionally corrects the times passed to Begin and ScheduleNext
+	// using an external time reference. See Discipliner.
+	Discipline Discipliner
+	// Resolution sets a coarse internal time resolution, such as a second or a minute,
+	// for long horizon schedules spanning weeks or months. Times passed to ScheduleNext
+	// are truncated to multiples of Resolution since the start time so sub-resolution
+	// jitter of the caller's clock has no effect on scheduling. Action durations must
+	// be multiples of Resolution. Zero value means nanosecond resolution.
+	Resolution time.Duration
+	// Recorder optionally records the internal decision of every ScheduleNext call.
+	Recorder *Recorder
+	// MaxDeadLetters is the number of most recent actions dropped by MissSkip or
+	// MissWarn kept for inspection with DeadLetters. Zero value disables dead
+	// letter collection.
+	MaxDeadLetters int
+	// Inject specifies the order of injected and regular actions due at the same time.
+	// The default is InjectAfter. See GroupSync.Inject.
+	Inject InjectPolicy
+	// Resume specifies where the group resumes after an interrupt.
+	// The default is ResumeCurrent. See GroupSync.Interrupt.
+	// GroupPriority applies it to preempted lanes.
+	Resume ResumePolicy
+	// AlignTo makes Begin round the start time up to the next multiple of AlignTo
+	// since the zero time so iterations start on clean boundaries such as whole minutes.
+	// Zero value means the group starts at the time passed to Begin.
+	AlignTo time.Duration
+	// Guard optionally conditions the delivery of each action on a predicate of
+	// the action's index, such as opening a valve only if pressure is ok. It is
+	// called when an action is due and its result handled per OnGuard.
+	Guard func(index int) bool
+	// OnGuard specifies how actions whose guard returns false are handled.
+	// The default is GuardSkip.
+	OnGuard GuardPolicy
 }
 
-// NewGroupSync returns a newly initialized group. Action duration must be greater than zero.
+// GuardPolicy specifies how a group handles actions whose guard returns false.
+// See GroupSyncConfig.Guard.
+type GuardPolicy uint8
+
+const (
+	// GuardSkip drops the action and waits for the following one. This is the default.
+	GuardSkip GuardPolicy = iota
+	// GuardHold holds the group at the action, evaluating the guard again on each
+	// call to ScheduleNext. Once the guard returns true the action is delivered
+	// with its full duration and the rest of the group is delayed accordingly.
+	GuardHold
+)
+
+// MissPolicy specifies how a group handles actions that were not scheduled
+// during their allotted time, i.e. missed actions. The policy determines the
+// delivery semantics of the group:
+//
+//   - MissSkip and MissWarn provide at-most-once delivery. Each action is returned by
+//     ScheduleNext at most once and missed actions are lost.
+//   - MissReplay provides at-least-once delivery. Missed actions are delivered
+//     late and flagged as backfilled. Actions delivered before a restart with
+//     Begin and the original start time are delivered again, so consumers
+//     that require exactly-once processing must deduplicate.
+//   - MissFail provides at-most-once delivery that never loses actions
+//     silently: the group fails instead.
+type MissPolicy uint8
+
+const (
+	// MissFail fails the group when an action is missed. ScheduleNext returns
+	// errors until Begin is called again.
+	MissFail MissPolicy = iota
+	// MissSkip drops missed actions and continues with the action that should
+	// currently be running. The group does not fail. Missed actions are thus
+	// coalesced into the latest value, the right semantics for setpoints.
+	// GroupSync.Collapsed and GroupSync.Skipped report how many actions were
+	// dropped and they may be collected for inspection by configuring MaxDeadLetters.
+	MissSkip
+	// MissReplay delivers missed actions in order, back to back, before resuming
+	// real time scheduling. This backfills actions that would have fired during a gap
+	// such as a pause or a reboot followed by Begin with the original start time.
+	// Replayed actions are returned with next equal to zero and GroupSync.Backfilled
+	// reports true for them. Replayed first actions do not reanchor the group.
+	MissReplay
+	// MissWarn works like MissSkip but also reports each miss once, as a
+	// *MissWarning error returned alongside the otherwise valid result of the
+	// ScheduleNext call that detected it. The group never fails due to misses
+	// and scheduling continues at the correct phase.
+	MissWarn
+
+	// AtMostOnce selects at-most-once delivery. It is equivalent to MissSkip.
+	AtMostOnce = MissSkip
+	// AtLeastOnce selects at-least-once delivery. It is equivalent to MissReplay.
+	AtLeastOnce = MissReplay
+)
+
+// NewGroupSync returns a newly initialized group. Action durations must not be negative
+// and at least one action must have a duration greater than zero.
 func NewGroupSync[T any](actions []Action[T], cfg GroupSyncConfig) (*GroupSync[T], error) {
-	duration, err := actionsDuration(actions, false)
+	duration, err := actionsDuration(actions)
+	offsets, _, anchorErr := actionOffsets(actions, time.Time{})
 	switch {
 	case err != nil && !errors.Is(err, ErrSmallDuration):
 		return nil, err
 	case len(actions) == 0:
 		return nil, errEmptyActions
+	case duration == 0:
+		return nil, errZeroDuration
+	case anchorErr != nil:
+		return nil, anchorErr
+	case hasAnchors(actions) && cfg.Iterations != 1:
+		return nil, errAnchorIterations
 	case cfg.Iterations <= 0 && cfg.Iterations != -1:
 		return nil, errBadIterations
+	case cfg.MaxPulseCorrection < 0 || cfg.AlignTo < 0:
+		return nil, errNegativeDuration
+	case cfg.OnMiss > MissWarn:
+		return nil, errBadMissPolicy
+	case cfg.MaxDeadLetters < 0:
+		return nil, errBadDeadLetters
+	case cfg.Inject > InjectBefore:
+		return nil, errBadInjectPolicy
+	case cfg.Resume > ResumePaused:
+		return nil, errBadResumePolicy
+	case cfg.Resolution < 0:
+		return nil, errNegativeDuration
+	case cfg.OnGuard > GuardHold:
+		return nil, errBadGuardPolicy
+	case cfg.Resolution > 0 && !multipleOf(actions, cfg.Resolution):
+		return nil, errBadResolution
+	}
+	if cfg.Iterations != -1 {
+		if _, err := iterationsDuration(duration, cfg.Iterations); err != nil {
+			return nil, err
+		}
 	}
 
 	g := &GroupSync[T]{
-		actions:    actions,
-		duration:   duration,
-		iterations: cfg.Iterations,
+		actions:         actions,
+		offsets:         offsets,
+		duration:        duration,
+		iterations:      cfg.Iterations,
+		stopOnBoundary:  cfg.StopOnBoundary,
+		detectUnderPoll: cfg.DetectUnderPolling,
+		reanchor:        cfg.Reanchor,
+		maxCorrection:   cfg.MaxPulseCorrection,
+		onMiss:          cfg.OnMiss,
+		discipline:      cfg.Discipline,
+		recorder:        cfg.Recorder,
+		alignTo:         cfg.AlignTo,
+		maxDeadLetters:  cfg.MaxDeadLetters,
+		injectPolicy:    cfg.Inject,
+		resume:          cfg.Resume,
+		resolution:      cfg.Resolution,
+		guard:           cfg.Guard,
+		onGuard:         cfg.OnGuard,
+		completeLate:    cfg.CompleteLate,
 	}
 	return g, err // return ErrSmallDuration as a warning to users.
 }
 
+// MustGroupSync is like NewGroupSync but panics if the group is invalid. It is
+// meant for initializing package level variables with static schedules.
+// ErrSmallDuration is not considered invalid.
+func MustGroupSync[T any](actions []Action[T], cfg GroupSyncConfig) *GroupSync[T] {
+	g, err := NewGroupSync(actions, cfg)
+	if err != nil && !errors.Is(err, ErrSmallDuration) {
+		panic(err)
+	}
+	return g
+}
+
 // GroupSync specifies a group of actions that should be executed one after another
 // while prioritizing the time between actions and the periodicity of the group.
 // This is to say that if the group ran for a long one could calculate how
@@ -61,30 +257,235 @@ func NewGroupSync[T any](actions []Action[T], cfg GroupSyncConfig) (*GroupSync[T
 //     shortened to not delay the scheduling of the next action.
 //   - If an action is not scheduled during its allotted time the group will fail
 //     and errors will be returned then onwards until Begin is called again.
+//     This can be changed with the OnMiss configuration.
+//   - Zero duration actions are scheduled back to back with the action that
+//     follows them. ScheduleNext returns them with ok=true and next=0 and
+//     should be called again immediately to receive the following action.
+//   - Actions anchored to an absolute time with Action.At start at that time.
+//     The group's Duration then depends on the start time passed to Begin.
 type GroupSync[T any] struct {
 	start time.Time
+	// lazy is set when Begin was called with a zero start time.
+	lazy bool
 	// elapsedToRestart necessary to prevent a bug where a whole schedule is missed.
 	// Add this to start to get time of last restart.
 	elapsedToRestart time.Duration
-	duration         time.Duration
-	lastIdx          int
-	actions          []Action[T]
-	iterations       int
-	failed           bool
+	// restartIter is the iteration that started at the last restart.
+	restartIter   int
+	reanchor      bool
+	maxCorrection time.Duration
+	onMiss        MissPolicy
+	discipline    Discipliner
+	duration      time.Duration
+	// lastPos is the position of the last scheduled action counting the actions
+	// of previous iterations. It is -1 before the first action is scheduled.
+	lastPos int
+	actions []Action[T]
+	// offsets holds the start time of each action relative to the start of its iteration.
+	offsets []time.Duration
+	// anchorErr is set by Begin when the start time is inconsistent with anchored actions.
+	anchorErr  error
+	iterations int
+	failed     bool
+	// stop is the time at which the group reports done. Zero value means no stop.
+	stop           time.Time
+	stopOnBoundary bool
+	// lastPoll is the time of the last ScheduleNext call, used to detect under-polling.
+	lastPoll        time.Time
+	detectUnderPoll bool
+	// lastNext is the next returned by the last ScheduleNext call checked for under-polling.
+	lastNext time.Duration
+	// lock is the group this group is phase-locked to, see NewHarmonic.
+	lock     phaseSource
+	divisor  int
+	recorder *Recorder
+	// track is the index of the group within a GroupParallel, recorded in decisions.
+	track   int
+	alignTo time.Duration
+	// startOffset delays the start time passed to Begin. See NewGroupOffset.
+	startOffset time.Duration
+	// fixedStart, if set, is the start time regardless of the time passed to Begin. See NewGroupAbsolute.
+	fixedStart time.Time
+	// deadLetters holds the most recent actions dropped by MissSkip or MissWarn.
+	deadLetters    []DeadLetter[T]
+	maxDeadLetters int
+	// backfilled is true if the last scheduled action was replayed by MissReplay.
+	backfilled bool
+	// collapsed is the number of actions dropped before the last scheduled action.
+	collapsed int
+	// skipped is the total number of actions dropped since Begin.
+	skipped int
+	// injected holds pending injected actions sorted by time.
+	injected      []injection[T]
+	injectedLast  bool
+	lastInjection time.Time
+	injectPolicy  InjectPolicy
+	interrupt     interrupt[T]
+	resume        ResumePolicy
+	resolution    time.Duration
+	completeLate  bool
+	guard         func(index int) bool
+	onGuard       GuardPolicy
+	// held is set while an action is held by its guard.
+	held bool
+	// lastDue is the time the last scheduled action was due.
+	lastDue time.Time
+	// lazyStop is the StopAfter duration set before a lazy start, -1 if none.
+	lazyStop time.Duration
 }
 
+// Action is a value scheduled by a group for a duration.
 type Action[T any] struct {
 	Duration time.Duration
 	Value    T
+	// At optionally anchors the start of the action to an absolute time instead
+	// of the end of the previous action, which leaves an idle gap between them.
+	// Anchors must be consistent with the durations of the actions preceding them.
+	// Only supported by GroupSync with a single iteration.
+	At time.Time
+	// Name optionally identifies the action in errors and String output.
+	Name string
+	// Tolerance is how late after its start the action may still be delivered
+	// when it was missed, instead of being handled by the group's MissPolicy.
+	// A tolerated action is returned with next zero so the action that follows
+	// it is scheduled immediately after. Only supported by GroupSync.
+	Tolerance time.Duration
+}
+
+func (a Action[T]) String() string {
+	if a.Name != "" {
+		return fmt.Sprintf("%s(%v) for %s", a.Name, a.Value, a.Duration)
+	}
+	return fmt.Sprintf("%v for %s", a.Value, a.Duration)
+}
+
+// Begin sets the start time of the group. It must be called before ScheduleNext.
+// It effectively resets internal state of the group. A zero start time anchors
+// the start to the time passed to the first ScheduleNext call so the first
+// action is not shortened by time passed between Begin and the first call.
+func (g *GroupSync[T]) Begin(start time.Time) {
+	if g.discipline != nil && !start.IsZero() {
+		start = g.discipline.Correct(start)
+	}
+	g.begin(start)
 }
 
-// Begins sets the start time of the group. It must be called before ScheduleNext.
-// It effectively resets internal state of the group.
+// Begins is an alias of Begin.
+//
+// Deprecated: Use Begin.
 func (g *GroupSync[T]) Begins(start time.Time) {
-	g.start = start
+	g.Begin(start)
+}
+
+// begin resets the group to start at a disciplined start time.
+func (g *GroupSync[T]) begin(start time.Time) {
+	g.lazy = start.IsZero()
+	g.start = time.Time{}
+	g.lazyStop = -1
 	g.elapsedToRestart = 0
-	g.lastIdx = -1
+	g.restartIter = 0
+	g.lastPos = -1
 	g.failed = false
+	g.stop = time.Time{}
+	g.lastPoll = time.Time{}
+	g.lastNext = 0
+	g.deadLetters = g.deadLetters[:0]
+	g.backfilled = false
+	g.collapsed = 0
+	g.skipped = 0
+	g.held = false
+	g.lastDue = time.Time{}
+	g.injected = g.injected[:0]
+	g.injectedLast = false
+	g.interrupt = interrupt[T]{}
+	if !g.lazy {
+		g.anchor(start)
+	}
+}
+
+// anchorLazy anchors a lazily begun group at local time now. Groups driving
+// several GroupSync use it to start them at the same time.
+func (g *GroupSync[T]) anchorLazy(now time.Time) {
+	if !g.lazy {
+		return
+	}
+	if g.discipline != nil {
+		now = g.discipline.Correct(now)
+	}
+	g.anchor(now)
+}
+
+// anchor sets the start time of the group and the state derived from it.
+// Unlike begin it keeps state set up by the caller after a lazy Begin.
+func (g *GroupSync[T]) anchor(start time.Time) {
+	g.lazy = false
+	if g.alignTo > 0 {
+		if aligned := start.Truncate(g.alignTo); aligned.Before(start) {
+			start = aligned.Add(g.alignTo)
+		}
+	}
+	start = start.Add(g.startOffset)
+	if !g.fixedStart.IsZero() {
+		start = g.fixedStart
+	}
+	g.start = start
+	if g.lazyStop >= 0 {
+		g.stop = start.Add(g.lazyStop)
+		g.lazyStop = -1
+	}
+	if hasAnchors(g.actions) {
+		offsets, duration, err := actionOffsets(g.actions, start)
+		g.anchorErr = err
+		if err == nil {
+			g.offsets, g.duration = offsets, duration
+		}
+	}
+}
+
+// AddIterations extends the number of iterations of the group by n while
+// preserving its phase. It has no effect on groups with infinite iterations.
+// It must be called before the group is done. An error wrapping ErrDurationOverflow
+// is returned if the extended group's total duration would overflow.
+func (g *GroupSync[T]) AddIterations(n int) error {
+	if n <= 0 {
+		return errBadIterations
+	}
+	if g.iterations == -1 {
+		return nil
+	}
+	if n > math.MaxInt-g.iterations {
+		return ErrDurationOverflow
+	}
+	if _, err := iterationsDuration(g.duration, g.iterations+n); err != nil {
+		return err
+	}
+	g.iterations += n
+	return nil
+}
+
+// FinishIteration lets the group complete the iteration of the most recently
+// scheduled action and then report done. It is meant for winding down infinite
+// groups without stopping between actions. After the call Iterations
+// returns the new, finite, iteration count.
+func (g *GroupSync[T]) FinishIteration() {
+	g.iterations = g.lastPos/len(g.actions) + 1
+}
+
+// StopAt arranges for the group to report done at time t. If the group was
+// configured with StopOnBoundary the action running at t is allowed to complete.
+// It must be called after Begin, which clears any previously set stop time.
+func (g *GroupSync[T]) StopAt(t time.Time) {
+	g.stop = t
+}
+
+// StopAfter arranges for the group to report done a duration d after the group's
+// start time. It is equivalent to calling StopAt(g.StartTime().Add(d)).
+func (g *GroupSync[T]) StopAfter(d time.Duration) {
+	if g.lazy {
+		g.lazyStop = d // Applied once the start time is known.
+		return
+	}
+	g.StopAt(g.start.Add(d))
 }
 
 // StartTime time returns the time the group was Started at. If not started returns zero value.
@@ -103,65 +504,703 @@ func (g *GroupSync[T]) Iterations() int {
 	return g.iterations
 }
 
+// CurrentIteration returns the number of iterations completed as of the last
+// call to ScheduleNext, counting from zero. It is useful for displaying loop
+// counters and triggering end of iteration logic.
+func (g *GroupSync[T]) CurrentIteration() int {
+	if g.iterations != -1 && g.restartIter > g.iterations {
+		return g.iterations
+	}
+	return g.restartIter
+}
+
+// IterationBoundary returns the time at which iteration iter starts or started,
+// counting from zero. Passing the iteration count returns the time the group ends.
+// Groups configured with Reanchor only know the boundaries of the current and
+// following iterations. The zero time is returned for unknown boundaries.
+func (g *GroupSync[T]) IterationBoundary(iter int) time.Time {
+	if iter < 0 || g.reanchor && iter < g.restartIter {
+		return time.Time{}
+	}
+	delta := iter - g.restartIter
+	if delta < 0 {
+		delta = -delta
+	}
+	sinceRestart, err := iterationsDuration(g.duration, delta)
+	if err != nil {
+		return time.Time{}
+	}
+	if iter < g.restartIter {
+		sinceRestart = -sinceRestart
+	}
+	return g.start.Add(g.elapsedToRestart + sinceRestart)
+}
+
+// RequiredResolution returns the maximum interval between ScheduleNext calls
+// that guarantees no action is missed. This is the shortest time an action may
+// be delivered in, its duration or its Tolerance if longer, over all actions
+// with non-zero duration. Event loops should poll the group at least this often.
+func (g *GroupSync[T]) RequiredResolution() (min time.Duration) {
+	for _, v := range g.actions {
+		if v.Duration == 0 {
+			continue // Delivered along with the following action.
+		}
+		window := v.Duration
+		if v.Tolerance > window {
+			window = v.Tolerance
+		}
+		if min == 0 || window < min {
+			min = window
+		}
+	}
+	return min
+}
+
+// IterationsRemaining returns the number of iterations left to run at time now,
+// counting the iteration in progress. It returns -1 for groups with infinite
+// iterations and 0 once the group is done.
+func (g *GroupSync[T]) IterationsRemaining(now time.Time) int {
+	if g.iterations == -1 {
+		return -1
+	}
+	if g.start.IsZero() || now.Before(g.start) {
+		return g.iterations
+	}
+	completed := g.iterationAt(now)
+	if completed >= g.iterations {
+		return 0
+	}
+	return g.iterations - completed
+}
+
+// iterationElapsed returns the time elapsed at now in the iteration running
+// at now following the group's timeline. running is false before the group
+// starts, after it is done, stopped or failed. It does not modify the group.
+func (g *GroupSync[T]) iterationElapsed(now time.Time) (elapsed time.Duration, running bool) {
+	stopped := !g.stop.IsZero() && !g.stopOnBoundary && !now.Before(g.stop)
+	if g.start.IsZero() || now.Before(g.start) || g.failed || stopped {
+		return 0, false
+	}
+	elapsed = now.Sub(g.start) - g.elapsedToRestart
+	if elapsed < 0 {
+		elapsed = 0 // Phase was corrected backwards.
+	}
+	if iter := g.restartIter + int(elapsed/g.duration); g.iterations != -1 && iter >= g.iterations {
+		return 0, false
+	}
+	return elapsed % g.duration, true
+}
+
+// iterationAt returns the iteration running at now following the group's
+// timeline, which accounts for restarts, late completions and holds.
+// It does not modify the group.
+func (g *GroupSync[T]) iterationAt(now time.Time) int {
+	return g.restartIter + int((now.Sub(g.start)-g.elapsedToRestart)/g.duration)
+}
+
 // ScheduleNext checks `now` against time GroupSync started and returns
 // the next executable action when `ok` is true and `next` duration until next
 // ready action.
 //
-// If ok is false and next is zero the group is done.
+// If ok is false and next is zero the group is done. If ok is true and next is zero
+// a zero duration action was scheduled and ScheduleNext should be called again.
+//
+// next is meaningful in every state so event loops may always sleep for next
+// instead of busy polling:
+//
+//   - Before the start time next is the time until the group starts.
+//   - While an action runs next is the time until the following action
+//     starts, the group ends or the stop time is reached.
+//   - When an action is scheduled next is the time it runs for, or zero if
+//     the following action is due immediately.
+//   - Once done, by running all iterations or by reaching the stop time,
+//     next is zero.
+//   - On errors, such as calls before Begin or after the group failed,
+//     next is zero since the group does not progress until Begin is called.
+//     Warnings such as ErrUnderPolling and *MissWarning are instead returned
+//     alongside valid ok and next values.
 func (g *GroupSync[T]) ScheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	if g.discipline != nil {
+		now = g.discipline.Correct(now)
+	}
+	if g.lazy {
+		g.anchor(now)
+	}
+	var lag time.Duration
+	if g.resolution > 0 && !g.start.IsZero() {
+		now, lag = g.quantize(now)
+	}
+	if g.lock != nil {
+		g.lockPhase()
+	}
+	if g.recorder != nil {
+		g.recorder.pending = Decision{Now: now, Elapsed: now.Sub(g.start), Track: g.track, LastPos: g.lastPos, Pos: -1}
+		defer func() {
+			d := g.recorder.pending
+			d.Ok, d.Next, d.Err = ok, next, err
+			if ok {
+				d.Value = formatValue(v)
+			}
+			g.recorder.record(d)
+		}()
+	}
+	if lag > 0 {
+		// Runs before the recorder's deferred call so the quantized next is recorded.
+		defer func() {
+			// Time until next is measured from quantized now. Times due within
+			// the lag, such as an off-grid stop time, were already reached.
+			if next > lag {
+				next -= lag
+			} else {
+				next = 0
+			}
+		}()
+	}
 	if g.start.IsZero() {
-		return v, false, 0, errBeginNotCalled
+		g.recorder.note(BranchNotBegun, -1)
+		return v, false, 0, ErrBeginNotCalled
 	}
 	if g.failed {
-		return v, false, next, errGroupFailed
+		g.recorder.note(BranchFailed, -1)
+		return v, false, 0, ErrGroupFailed
+	}
+	if g.anchorErr != nil {
+		g.recorder.note(BranchAnchorInvalid, -1)
+		return v, false, 0, g.anchorErr
+	}
+	if g.interrupt.pending || !g.interrupt.end.IsZero() {
+		if v, ok, next, handled := g.interrupted(now); handled {
+			return v, ok, next, nil
+		}
+	}
+	if g.injectPolicy == InjectBefore && g.injectionDue(now) {
+		return g.popInjection(), true, 0, nil
+	}
+	v, ok, next, err = g.scheduleNext(now)
+	if ok && g.guard != nil {
+		v, ok, next, err = g.applyGuard(now, v, next, err)
+	}
+	if (err == nil || ok) && len(g.injected) > 0 {
+		v, ok, next = g.mergeInjections(now, v, ok, next)
+	} else if ok {
+		g.injectedLast = false
+	}
+	if g.detectUnderPoll && err == nil && (ok || next != 0) {
+		err = g.checkPolling(now, next)
+	}
+	return v, ok, next, err
+}
+
+// applyGuard evaluates the guard of the action just scheduled with value v
+// and skips or holds it per the group's GuardPolicy.
+func (g *GroupSync[T]) applyGuard(now time.Time, v T, next time.Duration, err error) (_ T, ok bool, _ time.Duration, _ error) {
+	n := len(g.actions)
+	for {
+		pos := g.lastPos
+		if g.guard(pos % n) {
+			if g.held {
+				// Deliver the held action with its full duration.
+				g.held = false
+				g.holdAt(now, pos)
+				_, next = g.currentIdx(g.offsets[pos%n])
+				next = stopCap(now, g.stop, g.stopOnBoundary, next)
+			}
+			return v, true, next, err
+		}
+		g.recorder.note(BranchGuarded, pos)
+		var zero T
+		if g.onGuard == GuardHold {
+			g.lastPos = pos - 1 // Undo the delivery.
+			g.held = true
+			g.holdAt(now, pos)
+			return zero, false, minDuration(g.actions), err
+		}
+		if next != 0 {
+			return zero, false, next, err
+		}
+		// Skipped action was followed by an action due now.
+		if v, ok, next, err = g.scheduleNext(now); !ok {
+			return v, false, next, err
+		}
+	}
+}
+
+// noteDue records the time the last scheduled action was due before any shift
+// of the group's timeline. It is kept while the action is held by its guard.
+func (g *GroupSync[T]) noteDue() {
+	if !g.held {
+		g.lastDue = g.due(g.lastPos)
+	}
+}
+
+// holdAt moves the start of the action at pos to now.
+func (g *GroupSync[T]) holdAt(now time.Time, pos int) {
+	g.restartIter = pos / len(g.actions)
+	g.elapsedToRestart = now.Sub(g.start) - g.offsets[pos%len(g.actions)]
+}
+
+// checkPolling records the time of a ScheduleNext call and the next it returned.
+// It returns an error wrapping ErrUnderPolling if the group was polled both later
+// than the next returned by the previous call and slower than its required resolution.
+// Callers waiting exactly next between calls are thus never warned.
+func (g *GroupSync[T]) checkPolling(now time.Time, next time.Duration) error {
+	prev, prevNext := g.lastPoll, g.lastNext
+	g.lastPoll, g.lastNext = now, next
+	if prev.Before(g.start) {
+		return nil // Group was not running during last call.
+	}
+	allowed := g.RequiredResolution()
+	if prevNext > allowed {
+		allowed = prevNext
+	}
+	if interval := now.Sub(prev); interval > allowed {
+		return fmt.Errorf("%w: got %s, required %s", ErrUnderPolling, interval, allowed)
+	}
+	return nil
+}
+
+// ScheduleDue calls ScheduleNext until no more actions are ready at now and
+// appends the values of the actions scheduled to dst in order. Combined with
+// MissReplay it delivers all actions missed during a stall in a single burst
+// so consumers can apply cumulative state changes. next is the time until the
+// next action is ready; if no values are appended and next is zero the group is done.
+// Warnings returned alongside valid results are returned after the burst.
+func (g *GroupSync[T]) ScheduleDue(dst []T, now time.Time) (_ []T, next time.Duration, err error) {
+	for {
+		v, ok, nextAction, errAction := g.ScheduleNext(now)
+		if errAction != nil && (err == nil || !ok) {
+			err = errAction
+		}
+		if !ok {
+			return dst, nextAction, err
+		}
+		dst = append(dst, v)
+		if nextAction != 0 {
+			return dst, nextAction, err
+		}
 	}
-	return g.scheduleNext(now)
 }
 
 func (g *GroupSync[T]) scheduleNext(now time.Time) (v T, ok bool, next time.Duration, err error) {
+	hasStop := !g.stop.IsZero()
+	if hasStop && !g.stopOnBoundary && !now.Before(g.stop) {
+		g.recorder.note(BranchStopped, -1)
+		return v, false, 0, nil // Stopped.
+	}
 	elapsed := now.Sub(g.start)
 	if elapsed < 0 {
-		return v, false, -elapsed, nil // Still waiting for start time.
+		g.recorder.note(BranchBeforeStart, -1)
+		return v, false, stopCap(now, g.stop, g.stopOnBoundary, -elapsed), nil // Still waiting for start time.
 	}
-	runtime := g.Duration()
+	n := len(g.actions)
+	pos, elapsed, next, done := g.position(now)
+	if hasStop && !done && pos >= 0 {
+		actionStart := now.Add(g.offsets[pos%n] - elapsed)
+		if g.stopOnBoundary && !actionStart.Before(g.stop) {
+			g.recorder.note(BranchStoppedOnBoundary, pos)
+			return v, false, 0, nil // Stopped on action boundary.
+		}
+		next = stopCap(now, g.stop, g.stopOnBoundary, next)
+	}
+	if pos <= g.lastPos {
+		// Still need to execute current action or phase was corrected backwards.
+		g.recorder.note(BranchWaiting, pos)
+		return v, false, next, nil
+	}
+	expected := g.lastPos + 1
+	if expected < pos && g.onlyZeroDuration(expected, pos) {
+		// Zero duration actions are scheduled back to back with the action that follows them.
+		g.recorder.note(BranchZeroDuration, pos)
+		g.lastPos = expected
+		g.noteDue()
+		g.collapsed = 0
+		if g.reanchor && expected%n == 0 {
+			g.restart(now, expected/n)
+		}
+		return g.actions[expected%n].Value, true, 0, nil
+	}
+	if pos > expected && g.tolerated(expected, now) {
+		g.recorder.note(BranchTolerated, pos)
+		g.lastPos = expected
+		g.noteDue()
+		g.backfilled = false
+		g.collapsed = 0
+		return g.actions[expected%n].Value, true, 0, nil
+	}
+	if pos > expected && g.onMiss == MissReplay {
+		g.recorder.note(BranchReplayed, pos)
+		g.lastPos = expected
+		g.noteDue()
+		g.backfilled = true
+		g.collapsed = 0
+		return g.actions[expected%n].Value, true, 0, nil
+	}
+	if done {
+		g.recorder.note(BranchDone, pos)
+		return v, false, 0, nil // We are done, time exceeded.
+	}
+	if pos != expected && g.onMiss == MissFail {
+		// We check the worst case scenario where we missed an action.
+		g.recorder.note(BranchMissed, pos)
+		g.failed = true
+		return v, false, 0, g.actionError(expected, ErrMissedAction) // Missed action.
+	}
+	if pos != expected && g.maxDeadLetters > 0 {
+		g.addDeadLetters(expected, pos)
+	}
+	// It is time for the next action.
+	g.recorder.note(BranchScheduled, pos)
+	g.collapsed = pos - expected
+	g.skipped += g.collapsed
+	g.lastPos = pos
+	g.noteDue()
+	g.backfilled = false
+	if lateness := elapsed - g.offsets[pos%n]; g.completeLate && lateness > 0 {
+		// Delay the group so the action runs for its full duration.
+		g.elapsedToRestart += lateness
+		next = stopCap(now, g.stop, g.stopOnBoundary, next+lateness)
+	}
+	if g.reanchor && pos%n == 0 {
+		g.restart(now, pos/n)
+		_, next = g.currentIdx(0)
+		next = stopCap(now, g.stop, g.stopOnBoundary, next)
+	}
+	if g.collapsed > 0 && g.onMiss == MissWarn {
+		err = &MissWarning{Missed: g.collapsed, Index: expected % n, Iteration: expected / n, Name: g.actions[expected%n].Name}
+	}
+	return g.actions[pos%n].Value, true, next, err
+}
+
+// MissWarning is returned by groups configured with MissWarn alongside valid
+// results when actions were missed. errors.Is reports it as a missed action error.
+type MissWarning struct {
+	// Missed is the number of consecutive actions missed.
+	Missed int
+	// Index and Iteration locate the first missed action.
+	Index     int
+	Iteration int
+	// Name is the name of the first missed action.
+	Name string
+}
+
+func (w *MissWarning) Error() string {
+	return fmt.Sprintf("missed %d action(s) starting at %s", w.Missed, actionLabel(w.Index, w.Iteration, w.Name))
+}
+
+// Is reports whether target is the missed action error.
+func (w *MissWarning) Is(target error) bool {
+	return target == ErrMissedAction
+}
+
+// ActionError is an error caused by a specific action of a group, such as
+// missing it. It wraps the cause which can be checked with errors.Is.
+type ActionError struct {
+	// Index and Iteration locate the action.
+	Index     int
+	Iteration int
+	// Name is the name of the action.
+	Name string
+	Err  error
+}
 
-	restartActive := g.iterations == -1 || g.iterations > 1 && elapsed < time.Duration(g.iterations)*runtime
-	if restartActive {
-		elapsed = elapsed % runtime
+func (e *ActionError) Error() string {
+	return actionLabel(e.Index, e.Iteration, e.Name) + ": " + e.Err.Error()
+}
+
+func (e *ActionError) Unwrap() error { return e.Err }
+
+// actionError returns err annotated with the action at position pos.
+func (g *GroupSync[T]) actionError(pos int, err error) error {
+	n := len(g.actions)
+	return &ActionError{Index: pos % n, Iteration: pos / n, Name: g.actions[pos%n].Name, Err: err}
+}
+
+func actionLabel(index, iteration int, name string) string {
+	if name != "" {
+		return fmt.Sprintf("action %d %q of iteration %d", index, name, iteration)
 	}
+	return fmt.Sprintf("action %d of iteration %d", index, iteration)
+}
+
+// TriggerID identifies a firing of an action. It depends only on the schedule
+// so an action delivered twice, for example when replayed after a restart,
+// has the same TriggerID. Consumers may use it to deduplicate deliveries
+// and to correlate acknowledgements with specific firings.
+type TriggerID struct {
+	// Iteration is the iteration of the firing counting from zero.
+	Iteration int
+	// Index is the index of the fired action in the group's actions.
+	Index int
+	// Seq is the sequence number of the firing, counting the actions of all
+	// iterations since the group's start time including missed actions.
+	Seq int
+}
 
-	// Find index of next action.
-	nextIdx, next := currentIdx(g.actions, elapsed)
-	if nextIdx == g.lastIdx {
-		return v, false, next, nil // Still need to execute current action.
+func (id TriggerID) String() string {
+	return fmt.Sprintf("%d:%d#%d", id.Iteration, id.Index, id.Seq)
+}
+
+// LastTrigger returns the TriggerID of the action returned by the last successful
+// call to ScheduleNext. ok is false if no action was scheduled since Begin.
+func (g *GroupSync[T]) LastTrigger() (id TriggerID, ok bool) {
+	if g.lastPos < 0 {
+		return id, false
 	}
-	// We check the worst case scenario where we missed an action.
-	if nextIdx != -1 && !restartActive && nextIdx != g.lastIdx+1 ||
-		(nextIdx != -1 && restartActive && nextIdx != (g.lastIdx+1)%(len(g.actions))) {
-		g.failed = true
-		return v, false, 0, errMissedAction // Missed action.
-	} else if nextIdx == -1 {
-		// We are done, time exceeded.
-		return v, false, 0, nil
+	n := len(g.actions)
+	return TriggerID{Iteration: g.lastPos / n, Index: g.lastPos % n, Seq: g.lastPos}, true
+}
+
+// Backfilled reports whether the action returned by the last successful call to
+// ScheduleNext was missed and replayed by the MissReplay policy.
+func (g *GroupSync[T]) Backfilled() bool {
+	return g.backfilled
+}
+
+// Collapsed returns the number of missed actions dropped by the MissSkip or
+// MissWarn policies and coalesced into the action returned by the last
+// successful call to ScheduleNext.
+func (g *GroupSync[T]) Collapsed() int {
+	return g.collapsed
+}
+
+// Skipped returns the total number of missed actions dropped by the MissSkip
+// or MissWarn policies since Begin was called.
+func (g *GroupSync[T]) Skipped() int {
+	return g.skipped
+}
+
+// DeadLetter is an action dropped by the MissSkip or MissWarn policies. See GroupSync.DeadLetters.
+type DeadLetter[T any] struct {
+	// Index is the index of the action in the group's actions.
+	Index int
+	// Iteration is the iteration the action was due in, counting from zero.
+	Iteration int
+	// Due is the time the action should have started at. It is the zero time
+	// if unknown, which may happen with groups configured with Reanchor.
+	Due   time.Time
+	Value T
+}
+
+// DeadLetters returns the most recent actions dropped by the MissSkip policy in
+// the order they were due, up to the configured MaxDeadLetters. The returned
+// slice is only valid until the next call to ScheduleNext or Begin.
+func (g *GroupSync[T]) DeadLetters() []DeadLetter[T] {
+	return g.deadLetters
+}
+
+// ClearDeadLetters discards collected dead letters, typically after they were inspected.
+func (g *GroupSync[T]) ClearDeadLetters() {
+	g.deadLetters = g.deadLetters[:0]
+}
+
+// addDeadLetters records the actions at positions [start, end) as dropped.
+// Only the positions that fit in maxDeadLetters are visited since the number
+// of dropped actions after a long gap may be very large.
+func (g *GroupSync[T]) addDeadLetters(start, end int) {
+	if end-start > g.maxDeadLetters {
+		start = end - g.maxDeadLetters
+	}
+	n := len(g.actions)
+	for pos := start; pos < end; pos++ {
+		iter, idx := pos/n, pos%n
+		g.deadLetters = append(g.deadLetters, DeadLetter[T]{Index: idx, Iteration: iter, Due: g.due(pos), Value: g.actions[idx].Value})
+	}
+	if excess := len(g.deadLetters) - g.maxDeadLetters; excess > 0 {
+		g.deadLetters = append(g.deadLetters[:0], g.deadLetters[excess:]...)
+	}
+}
+
+// Watermark returns the schedule time up to which all due actions have been
+// delivered or accounted for, like watermarks of streaming systems: every action
+// due before the watermark was returned by ScheduleNext or dropped by the MissSkip
+// policy. It is the time the next undelivered action is due, or the time the
+// group ends once all actions were delivered. The zero time is returned if the
+// group was not started or the time is unknown, see IterationBoundary.
+func (g *GroupSync[T]) Watermark() time.Time {
+	if g.start.IsZero() {
+		return time.Time{}
+	}
+	next := g.lastPos + 1
+	if g.iterations != -1 && next >= g.iterations*len(g.actions) {
+		return g.IterationBoundary(g.iterations)
 	}
+	return g.due(next)
+}
 
-	if nextIdx == g.lastIdx+1 || (restartActive && nextIdx == 0 && g.lastIdx == len(g.actions)-1) {
-		// It is time for the next action.
-		g.lastIdx = nextIdx
-		return g.actions[nextIdx].Value, true, next, nil
+// due returns the time the action at pos is due or the zero time if unknown.
+func (g *GroupSync[T]) due(pos int) time.Time {
+	n := len(g.actions)
+	due := g.IterationBoundary(pos / n)
+	if due.IsZero() {
+		return due
 	}
-	return v, false, next, fmt.Errorf("unexpected nextIdx: %d, lastIdx: %d", nextIdx, g.lastIdx)
+	return due.Add(g.offsets[pos%n])
 }
 
-func actionsDuration[T any](actions []Action[T], canZero bool) (duration time.Duration, err error) {
+// tolerated reports whether the missed action at pos is still within its tolerance at now.
+func (g *GroupSync[T]) tolerated(pos int, now time.Time) bool {
+	tolerance := g.actions[pos%len(g.actions)].Tolerance
+	if tolerance == 0 {
+		return false
+	}
+	due := g.due(pos)
+	return !due.IsZero() && now.Sub(due) <= tolerance
+}
+
+// position returns the position of the action that should be running at now,
+// the time elapsed since the start of its iteration and the time until the
+// following action starts. If done is true the group's iterations are exhausted
+// and the position returned is past the last action. now must not be before start.
+func (g *GroupSync[T]) position(now time.Time) (pos int, elapsed, next time.Duration, done bool) {
+	n := len(g.actions)
+	elapsed = now.Sub(g.start) - g.elapsedToRestart
+	if elapsed >= g.duration {
+		// Advance restart to the start of the current iteration. Keeping elapsed
+		// within a single iteration avoids multiplying the iteration count by the
+		// group duration, which could overflow on long running infinite groups.
+		completed := elapsed / g.duration
+		g.elapsedToRestart += completed * g.duration
+		g.restartIter += int(completed)
+		elapsed -= completed * g.duration
+	}
+	if rtChecks {
+		rtAssert(elapsed < g.duration, "elapsed time exceeds iteration duration")
+	}
+	done = g.iterations != -1 && g.restartIter >= g.iterations
+	if done {
+		return g.iterations * n, elapsed, 0, true
+	}
+	idx, next := g.currentIdx(elapsed)
+	return g.restartIter*n + idx, elapsed, next, false
+}
+
+// Resync snaps the group's elapsed time at now to referenceElapsed, an elapsed
+// time since start supplied by an external reference such as a master controller.
+// It returns the number of actions skipped by a forward jump which are handled
+// per the group's MissPolicy on the next call to ScheduleNext: with MissFail the
+// group fails, with MissSkip they are dropped and with MissReplay they are
+// delivered back to back. Backward jumps delay the
+// scheduling of the next action until the reference catches up.
+func (g *GroupSync[T]) Resync(now time.Time, referenceElapsed time.Duration) (skipped int) {
+	if g.start.IsZero() {
+		return 0
+	}
+	g.elapsedToRestart = now.Sub(g.start) - referenceElapsed
+	g.restartIter = 0
+	if referenceElapsed < 0 {
+		return 0
+	}
+	pos, _, _, _ := g.position(now)
+	if skipped = pos - g.lastPos - 1; skipped < 0 || g.onlyZeroDuration(g.lastPos+1, pos) {
+		return 0
+	}
+	return skipped
+}
+
+// SyncPulse nudges the group's phase toward an external synchronization pulse
+// received at now. The pulse is expected at iteration boundaries. The group's phase
+// is shifted toward the nearest boundary by at most the configured MaxPulseCorrection
+// so that devices sharing a sync line converge instead of drifting apart.
+// It returns the applied correction: positive values delay the group.
+func (g *GroupSync[T]) SyncPulse(now time.Time) (correction time.Duration) {
+	if g.start.IsZero() || now.Before(g.start) {
+		return 0
+	}
+	correction = (now.Sub(g.start) - g.elapsedToRestart) % g.duration
+	if correction < 0 {
+		correction += g.duration
+	}
+	if correction > g.duration/2 {
+		correction -= g.duration // Group is behind, nearest boundary is ahead.
+	}
+	if g.maxCorrection > 0 {
+		if correction > g.maxCorrection {
+			correction = g.maxCorrection
+		} else if correction < -g.maxCorrection {
+			correction = -g.maxCorrection
+		}
+	}
+	g.elapsedToRestart += correction
+	return correction
+}
+
+// restart sets now as the start time of iteration iter.
+func (g *GroupSync[T]) restart(now time.Time, iter int) {
+	g.elapsedToRestart = now.Sub(g.start)
+	g.restartIter = iter
+}
+
+// onlyZeroDuration reports whether all actions in positions [start, end) have zero duration.
+func (g *GroupSync[T]) onlyZeroDuration(start, end int) bool {
+	n := len(g.actions)
+	if end-start > n {
+		return false // A whole iteration can't consist of zero duration actions.
+	}
+	if rtChecks {
+		rtAssert(start <= end, "zero duration scan bounds reversed")
+	}
+	for pos := start; pos < end; pos++ {
+		if g.actions[pos%n].Duration != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// quantize truncates now to a multiple of the group's resolution since the start
+// time, rounding towards the past. It returns the truncated time and the time truncated.
+func (g *GroupSync[T]) quantize(now time.Time) (time.Time, time.Duration) {
+	elapsed := now.Sub(g.start)
+	lag := elapsed % g.resolution
+	if lag < 0 {
+		lag += g.resolution
+	}
+	return now.Add(-lag), lag
+}
+
+func multipleOf[T any](actions []Action[T], resolution time.Duration) bool {
+	for _, a := range actions {
+		if a.Duration%resolution != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TotalDuration returns the total time it takes to run actions for the given number
+// of iterations. It returns an error wrapping ErrDurationOverflow if the result
+// does not fit in a time.Duration. Iterations must be greater than zero.
+func TotalDuration[T any](actions []Action[T], iterations int) (time.Duration, error) {
+	if iterations <= 0 {
+		return 0, errBadIterations
+	}
+	duration, err := actionsDuration(actions)
+	if err != nil && !errors.Is(err, ErrSmallDuration) {
+		return 0, err
+	}
+	return iterationsDuration(duration, iterations)
+}
+
+// maxDuration is the maximum representable time.Duration.
+const maxDuration time.Duration = math.MaxInt64
+
+// iterationsDuration returns duration multiplied by iterations checking for overflow.
+func iterationsDuration(duration time.Duration, iterations int) (time.Duration, error) {
+	if duration != 0 && time.Duration(iterations) > maxDuration/duration {
+		return 0, fmt.Errorf("%w: %d iterations of %s", ErrDurationOverflow, iterations, duration)
+	}
+	return time.Duration(iterations) * duration, nil
+}
+
+func actionsDuration[T any](actions []Action[T]) (duration time.Duration, err error) {
 	var hasSmallDuration bool
 	for _, v := range actions {
 		switch {
-		case !canZero && v.Duration == 0:
-			return 0, errZeroDuration
-		case v.Duration < 0:
+		case v.Duration < 0 || v.Tolerance < 0:
 			return 0, errNegativeDuration
-		case v.Duration < time.Millisecond:
+		case v.Duration > maxDuration-duration:
+			return 0, ErrDurationOverflow
+		case v.Duration > 0 && v.Duration < time.Millisecond:
 			hasSmallDuration = true
 		}
 		duration += v.Duration
@@ -172,13 +1211,75 @@ func actionsDuration[T any](actions []Action[T], canZero bool) (duration time.Du
 	return duration, err
 }
 
-func currentIdx[T any](actions []Action[T], elapsed time.Duration) (int, time.Duration) {
-	var endOfAction time.Duration = 0
-	for i, action := range actions {
-		endOfAction += action.Duration
-		if elapsed < endOfAction {
-			return i, endOfAction - elapsed
+// stopCap limits next so that it does not exceed the time left until stop.
+// Groups stopping on action boundaries are not affected.
+func stopCap(now, stop time.Time, onBoundary bool, next time.Duration) time.Duration {
+	if stop.IsZero() || onBoundary {
+		return next
+	}
+	if untilStop := stop.Sub(now); untilStop < next {
+		return untilStop
+	}
+	return next
+}
+
+// minDuration returns the shortest non-zero action duration. It returns zero
+// if all actions have zero duration.
+func minDuration[T any](actions []Action[T]) (min time.Duration) {
+	for _, v := range actions {
+		if v.Duration > 0 && (min == 0 || v.Duration < min) {
+			min = v.Duration
+		}
+	}
+	return min
+}
+
+// currentIdx returns the index of the last action that started at or before elapsed
+// within an iteration and the time until the following action starts.
+// It returns -1 if no action has started yet.
+func (g *GroupSync[T]) currentIdx(elapsed time.Duration) (int, time.Duration) {
+	idx := sort.Search(len(g.offsets), func(i int) bool { return g.offsets[i] > elapsed }) - 1
+	nextStart := g.duration
+	if idx+1 < len(g.offsets) {
+		nextStart = g.offsets[idx+1]
+	}
+	return idx, nextStart - elapsed
+}
+
+// hasAnchors reports whether any action is anchored to an absolute time.
+func hasAnchors[T any](actions []Action[T]) bool {
+	for _, v := range actions {
+		if !v.At.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// actionOffsets returns the start time of each action relative to start and
+// the time at which the last action ends. Actions anchored with At start at that
+// time. If start is the zero value only the consistency between anchors is checked.
+func actionOffsets[T any](actions []Action[T], start time.Time) (offsets []time.Duration, end time.Duration, err error) {
+	offsets = make([]time.Duration, len(actions))
+	var lastAnchor time.Time
+	var sinceAnchor time.Duration // Time since last anchored action started.
+	for i, v := range actions {
+		if !v.At.IsZero() {
+			switch {
+			case !lastAnchor.IsZero() && v.At.Sub(lastAnchor) < sinceAnchor:
+				return nil, 0, errAnchorOrder
+			case !start.IsZero() && v.At.Sub(start) < end:
+				return nil, 0, errAnchorStart
+			}
+			if !start.IsZero() {
+				end = v.At.Sub(start)
+			}
+			lastAnchor = v.At
+			sinceAnchor = 0
 		}
+		offsets[i] = end
+		end += v.Duration
+		sinceAnchor += v.Duration
 	}
-	return -1, 0
+	return offsets, end, nil
 }
@@ -0,0 +1,53 @@
+package schedule
+
+import "time"
+
+// phaseSource is implemented by groups that harmonic groups can be phase-locked to.
+type phaseSource interface {
+	// phase returns the group's start time, the time from start to the start
+	// of iteration restartIter and restartIter itself.
+	phase() (start time.Time, elapsedToRestart time.Duration, restartIter int)
+	Iterations() int
+	Duration() time.Duration
+}
+
+func (g *GroupSync[T]) phase() (time.Time, time.Duration, int) {
+	return g.start, g.elapsedToRestart, g.restartIter
+}
+
+// NewHarmonic returns a GroupSync whose period is the master's period divided
+// by divisor and that is phase-locked to master: it starts when the master starts,
+// runs divisor iterations per master iteration and follows the master's
+// reanchoring and changes to its iteration count. The harmonic group is restarted
+// automatically when master's Begin is called, so only the master needs to be begun.
+//
+// The sum of action durations multiplied by divisor must equal the master's duration.
+func NewHarmonic[T, M any](master *GroupSync[M], actions []Action[T], divisor int) (*GroupSync[T], error) {
+	if divisor <= 0 {
+		return nil, errBadIterations
+	}
+	g, err := NewGroupSync(actions, GroupSyncConfig{Iterations: -1})
+	if err != nil && g == nil {
+		return nil, err
+	}
+	if hasAnchors(master.actions) || time.Duration(divisor)*g.duration != master.Duration() {
+		return nil, errHarmonicPeriod
+	}
+	g.lock = master
+	g.divisor = divisor
+	return g, err // return ErrSmallDuration as a warning to users.
+}
+
+// lockPhase synchronizes the group's phase and iterations with the group it is locked to.
+func (g *GroupSync[T]) lockPhase() {
+	start, elapsedToRestart, restartIter := g.lock.phase()
+	if !start.Equal(g.start) {
+		g.Begin(start) // Master was restarted.
+	}
+	g.elapsedToRestart = elapsedToRestart
+	g.restartIter = restartIter * g.divisor
+	g.iterations = -1
+	if iterations := g.lock.Iterations(); iterations != -1 {
+		g.iterations = iterations * g.divisor
+	}
+}
@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// InjectPolicy specifies the order in which injected actions and regular
+// actions due at the same time are scheduled. See GroupSync.Inject.
+type InjectPolicy uint8
+
+const (
+	// InjectAfter schedules a due injected action after the regular action due
+	// at the same time. This is the default.
+	InjectAfter InjectPolicy = iota
+	// InjectBefore schedules a due injected action before the regular action
+	// due at the same time.
+	InjectBefore
+)
+
+type injection[T any] struct {
+	at    time.Time
+	value T
+}
+
+// Inject queues a one-off action with value to be scheduled at time at, in
+// addition to the group's regular actions. Injected actions have no duration
+// and do not alter the group's timeline; they are returned by ScheduleNext
+// once due, in order of their time. When an injected action and a regular
+// action are due at the same call the group's InjectPolicy decides which is
+// returned first, the other being returned by the following call which is
+// signalled by a next value of zero. Injected actions not yet due when the
+// group is done are discarded. Begin discards all pending injected actions.
+func (g *GroupSync[T]) Inject(value T, at time.Time) {
+	i := sort.Search(len(g.injected), func(i int) bool { return g.injected[i].at.After(at) })
+	g.injected = append(g.injected, injection[T]{})
+	copy(g.injected[i+1:], g.injected[i:])
+	g.injected[i] = injection[T]{at: at, value: value}
+}
+
+// Injected reports whether the action returned by the last successful call
+// to ScheduleNext was an injected action or an interrupt. See Inject and Interrupt.
+func (g *GroupSync[T]) Injected() bool {
+	return g.injectedLast
+}
+
+// injectionDue reports whether an injected action is due at now.
+func (g *GroupSync[T]) injectionDue(now time.Time) bool {
+	return len(g.injected) > 0 && !now.Before(g.injected[0].at)
+}
+
+// popInjection removes the first injected action from the queue and returns its value.
+func (g *GroupSync[T]) popInjection() T {
+	g.recorder.note(BranchInjected, -1)
+	v := g.injected[0].value
+	g.lastInjection = g.injected[0].at
+	g.injected = append(g.injected[:0], g.injected[1:]...)
+	g.injectedLast = true
+	return v
+}
+
+// mergeInjections combines the results of scheduling regular actions with
+// pending injected actions.
+func (g *GroupSync[T]) mergeInjections(now time.Time, v T, ok bool, next time.Duration) (T, bool, time.Duration) {
+	due := g.injectionDue(now)
+	switch {
+	case ok:
+		g.injectedLast = false
+		if due {
+			return v, ok, 0 // Injection is returned on following call.
+		}
+	case due:
+		return g.popInjection(), true, 0
+	case next == 0:
+		g.injected = g.injected[:0] // Group is done.
+		return v, ok, next
+	}
+	if len(g.injected) > 0 {
+		if until := g.injected[0].at.Sub(now); next == 0 || until < next {
+			next = until
+		}
+	}
+	return v, ok, next
+}
+
+// ResumePolicy specifies where a group resumes after an interrupt. See GroupSync.Interrupt.
+type ResumePolicy uint8
+
+const (
+	// ResumeCurrent resumes with the action that would be running had the group not
+	// been interrupted. Actions due during the interrupt are dropped. This is the default.
+	ResumeCurrent ResumePolicy = iota
+	// ResumePaused resumes the group where it was interrupted, delaying the
+	// rest of the group by the interrupt's duration.
+	ResumePaused
+)
+
+type interrupt[T any] struct {
+	pending bool
+	value   T
+	d       time.Duration
+	// start and end of the active interrupt. end is zero if no interrupt is active.
+	start, end time.Time
+	// pausedPos is the position of the last scheduled action when the interrupt started.
+	pausedPos int
+}
+
+// Interrupt makes value the current value for a duration d, starting at the
+// next call to ScheduleNext which returns value. After d elapses the group's
+// regular actions resume per the group's ResumePolicy and the action resumed
+// at is scheduled again. This is useful for manual override buttons.
+// Interrupting an interrupted group replaces the active interrupt.
+// Begin discards any interrupt.
+func (g *GroupSync[T]) Interrupt(value T, d time.Duration) {
+	paused := g.interrupt.pausedPos
+	if g.interrupt.end.IsZero() {
+		paused = g.lastPos
+	}
+	g.interrupt = interrupt[T]{pending: true, value: value, d: d, pausedPos: paused, start: g.interrupt.start, end: g.interrupt.end}
+}
+
+// interrupted handles interrupts at now. If handled is false the group is
+// not interrupted and regular actions should be scheduled.
+func (g *GroupSync[T]) interrupted(now time.Time) (v T, ok bool, next time.Duration, handled bool) {
+	it := &g.interrupt
+	switch {
+	case it.pending:
+		it.pending = false
+		if it.end.IsZero() {
+			it.start = now
+		}
+		it.end = now.Add(it.d)
+		g.recorder.note(BranchInterrupted, -1)
+		g.injectedLast = true
+		g.lastInjection = now
+		return it.value, true, it.d, true
+	case now.Before(it.end):
+		g.recorder.note(BranchInterrupted, -1)
+		return v, false, it.end.Sub(now), true
+	}
+	// Interrupt is over, resume regular actions.
+	if g.resume == ResumePaused {
+		g.elapsedToRestart += now.Sub(it.start)
+		if it.pausedPos >= 0 {
+			g.lastPos = it.pausedPos - 1
+		}
+	} else if !now.Before(g.start) {
+		if pos, _, _, done := g.position(now); !done && pos >= 0 {
+			g.lastPos = pos - 1
+		}
+	}
+	*it = interrupt[T]{}
+	return v, false, 0, false
+}
@@ -0,0 +1,88 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestInject(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	for _, policy := range []schedule.InjectPolicy{schedule.InjectAfter, schedule.InjectBefore} {
+		g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Inject: policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Unix(100, 0)
+		g.Begin(start)
+		g.Inject(100, start.Add(time.Second)) // Conflicts with second action.
+		g.Inject(50, start.Add(500*time.Millisecond))
+		g.Inject(999, start.Add(3*time.Second)) // After group ends, discarded.
+		type result struct {
+			v        int
+			ok       bool
+			next     time.Duration
+			injected bool
+		}
+		var got []result
+		for _, now := range []time.Duration{0, 0, 500 * time.Millisecond, 1000 * time.Millisecond, 1000 * time.Millisecond, 1500 * time.Millisecond, 2 * time.Second} {
+			v, ok, next, err := g.ScheduleNext(start.Add(now))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, result{v, ok, next, g.Injected()})
+		}
+		want := []result{
+			{1, true, 500 * time.Millisecond, false},
+			{0, false, 500 * time.Millisecond, false}, // Waiting for injection.
+			{50, true, 0, true},
+			{2, true, 0, false},
+			{100, true, 0, true},
+			{0, false, 500 * time.Millisecond, true},
+			{0, false, 0, true}, // Done.
+		}
+		if policy == schedule.InjectBefore {
+			want[3], want[4] = result{100, true, 0, true}, result{2, true, time.Second, false}
+			want[5].injected, want[6].injected = false, false
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("policy %d call %d: got %+v, want %+v", policy, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	for _, test := range []struct {
+		resume     schedule.ResumePolicy
+		resumeV    int
+		resumeNext time.Duration
+	}{
+		{schedule.ResumeCurrent, 2, 300 * time.Millisecond},
+		{schedule.ResumePaused, 1, 800 * time.Millisecond},
+	} {
+		g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Resume: test.resume})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Unix(100, 0)
+		g.Begin(start)
+		g.ScheduleNext(start)
+		g.Interrupt(9, 1500*time.Millisecond)
+		v, ok, next, err := g.ScheduleNext(start.Add(200 * time.Millisecond))
+		if err != nil || v != 9 || !ok || next != 1500*time.Millisecond || !g.Injected() {
+			t.Errorf("resume %d: got (%d, %t, %s, %v) at interrupt start", test.resume, v, ok, next, err)
+		}
+		_, ok, next, err = g.ScheduleNext(start.Add(time.Second))
+		if err != nil || ok || next != 700*time.Millisecond {
+			t.Errorf("resume %d: got (%t, %s, %v) during interrupt, want waiting 700ms", test.resume, ok, next, err)
+		}
+		v, ok, next, err = g.ScheduleNext(start.Add(1700 * time.Millisecond))
+		if err != nil || v != test.resumeV || !ok || next != test.resumeNext || g.Injected() {
+			t.Errorf("resume %d: got (%d, %t, %s, %v) after interrupt, want (%d, true, %s, nil)", test.resume, v, ok, next, err, test.resumeV, test.resumeNext)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package midi converts between Standard MIDI Files and schedules so music
+// hardware can play standard files through the scheduling core.
+package midi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+var (
+	errNotMIDI         = errors.New("not a standard MIDI file")
+	errMultiTrack      = errors.New("only single track MIDI files are supported")
+	errSMPTE           = errors.New("SMPTE time division is not supported")
+	errRunningStatus   = errors.New("running status without previous status byte")
+	errVarLenTooLong   = errors.New("variable length quantity exceeds 4 bytes")
+	errUnexpectedChunk = errors.New("expected MTrk chunk")
+)
+
+// defaultTempo is the tempo of a MIDI file before the first tempo event, 120 BPM.
+const defaultTempo = 500000 // microseconds per quarter note.
+
+// Message is a MIDI channel voice message. The zero value is a rest which
+// carries no message and is used to represent silence before the first event.
+type Message struct {
+	Status byte
+	Data   [2]byte
+}
+
+// IsRest reports whether m is a rest carrying no MIDI message.
+func (m Message) IsRest() bool { return m.Status == 0 }
+
+// Len returns the number of data bytes of the message.
+func (m Message) Len() int {
+	switch m.Status & 0xf0 {
+	case 0xc0, 0xd0:
+		return 1
+	case 0x80, 0x90, 0xa0, 0xb0, 0xe0:
+		return 2
+	}
+	return 0
+}
+
+// ReadFile reads a single track Standard MIDI File (format 0, or format 1 with
+// one track) and returns its channel voice messages as a schedule of actions.
+// Each action lasts until the following message so simultaneous messages
+// result in zero duration actions. Tempo changes are applied when converting
+// delta times. If the first message does not start at time zero the schedule
+// starts with a rest. System exclusive and meta events other than tempo are ignored.
+func ReadFile(r io.Reader) ([]schedule.Action[Message], error) {
+	br := bufio.NewReader(r)
+	var hdr struct {
+		ID       [4]byte
+		Length   uint32
+		Format   uint16
+		Tracks   uint16
+		Division uint16
+	}
+	if err := binary.Read(br, binary.BigEndian, &hdr); err != nil {
+		return nil, err
+	}
+	switch {
+	case string(hdr.ID[:]) != "MThd" || hdr.Length < 6:
+		return nil, errNotMIDI
+	case hdr.Format > 1 || hdr.Tracks != 1:
+		return nil, errMultiTrack
+	case hdr.Division&0x8000 != 0:
+		return nil, errSMPTE
+	}
+	if _, err := br.Discard(int(hdr.Length) - 6); err != nil {
+		return nil, err
+	}
+	var trk struct {
+		ID     [4]byte
+		Length uint32
+	}
+	if err := binary.Read(br, binary.BigEndian, &trk); err != nil {
+		return nil, err
+	}
+	if string(trk.ID[:]) != "MTrk" {
+		return nil, errUnexpectedChunk
+	}
+	tr := &trackReader{r: bufio.NewReader(io.LimitReader(br, int64(trk.Length))), division: int64(hdr.Division), tempo: defaultTempo}
+	return tr.read()
+}
+
+type trackReader struct {
+	r        *bufio.Reader
+	division int64
+	tempo    int64
+	// ticks since the last tempo change and the time at which it happened.
+	ticks     int64
+	tempoTime time.Duration
+	status    byte
+}
+
+// now returns the time elapsed since the start of the track. It is computed from
+// the last tempo change so rounding errors do not accumulate.
+func (t *trackReader) now() time.Duration {
+	return t.tempoTime + time.Duration(t.ticks*t.tempo*int64(time.Microsecond)/t.division)
+}
+
+func (t *trackReader) read() ([]schedule.Action[Message], error) {
+	var (
+		actions []schedule.Action[Message]
+		times   []time.Duration
+	)
+	for {
+		delta, err := readVarLen(t.r)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		t.ticks += int64(delta)
+		msg, end, err := t.readEvent()
+		if err != nil {
+			return nil, err
+		}
+		if end {
+			break
+		}
+		if msg.IsRest() {
+			continue
+		}
+		now := t.now()
+		if len(actions) == 0 && now > 0 {
+			actions = append(actions, schedule.Action[Message]{})
+			times = append(times, 0)
+		}
+		actions = append(actions, schedule.Action[Message]{Value: msg})
+		times = append(times, now)
+	}
+	end := t.now()
+	for i := range actions {
+		next := end
+		if i+1 < len(times) {
+			next = times[i+1]
+		}
+		actions[i].Duration = next - times[i]
+	}
+	return actions, nil
+}
+
+// readEvent reads the event following a delta time. Events other than channel
+// voice messages are returned as rests. end is true at the end of track event.
+func (t *trackReader) readEvent() (msg Message, end bool, err error) {
+	b, err := t.r.ReadByte()
+	if err != nil {
+		return msg, false, err
+	}
+	switch {
+	case b == 0xff: // Meta event.
+		typ, err := t.r.ReadByte()
+		if err != nil {
+			return msg, false, err
+		}
+		data, err := t.readData()
+		if err != nil {
+			return msg, false, err
+		}
+		switch {
+		case typ == 0x2f:
+			return msg, true, nil
+		case typ == 0x51 && len(data) == 3:
+			t.tempoTime = t.now()
+			t.ticks = 0
+			t.tempo = int64(data[0])<<16 | int64(data[1])<<8 | int64(data[2])
+		}
+		return msg, false, nil
+	case b == 0xf0 || b == 0xf7: // System exclusive.
+		_, err = t.readData()
+		return msg, false, err
+	case b&0x80 != 0:
+		t.status = b
+		msg.Status = b
+	default:
+		if t.status == 0 {
+			return msg, false, errRunningStatus
+		}
+		msg.Status = t.status
+		msg.Data[0] = b
+		if msg.Len() == 2 {
+			msg.Data[1], err = t.r.ReadByte()
+		}
+		return msg, false, err
+	}
+	for i := 0; i < msg.Len() && err == nil; i++ {
+		msg.Data[i], err = t.r.ReadByte()
+	}
+	return msg, false, err
+}
+
+func (t *trackReader) readData() ([]byte, error) {
+	n, err := readVarLen(t.r)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, n)
+	_, err = io.ReadFull(t.r, data)
+	return data, err
+}
+
+func readVarLen(r io.ByteReader) (v uint32, err error) {
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		v = v<<7 | uint32(b&0x7f)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errVarLenTooLong
+}
+
+func (m Message) String() string {
+	if m.IsRest() {
+		return "rest"
+	}
+	return fmt.Sprintf("%#02x % x", m.Status, m.Data[:m.Len()])
+}
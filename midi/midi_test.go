@@ -0,0 +1,67 @@
+package midi_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule/midi"
+)
+
+func TestReadFile(t *testing.T) {
+	track := []byte{
+		0x00, 0xff, 0x51, 0x03, 0x07, 0xa1, 0x20, // Tempo 120 BPM.
+		0x30, 0x90, 0x3c, 0x64, // Note on C4 after an eighth note.
+		0x00, 0x40, 0x64, // Note on E4 with running status.
+		0x00, 0xff, 0x51, 0x03, 0x0f, 0x42, 0x40, // Tempo 60 BPM.
+		0x60, 0x80, 0x3c, 0x00, // Note off C4 after a quarter note.
+		0x00, 0xff, 0x2f, 0x00, // End of track.
+	}
+	var file bytes.Buffer
+	file.WriteString("MThd\x00\x00\x00\x06\x00\x00\x00\x01\x00\x60") // Format 0, 1 track, 96 PPQ.
+	file.WriteString("MTrk\x00\x00\x00")
+	file.WriteByte(byte(len(track)))
+	file.Write(track)
+
+	actions, err := midi.ReadFile(&file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		msg midi.Message
+		d   time.Duration
+	}{
+		{midi.Message{}, 250 * time.Millisecond},
+		{midi.Message{Status: 0x90, Data: [2]byte{0x3c, 0x64}}, 0},
+		{midi.Message{Status: 0x90, Data: [2]byte{0x40, 0x64}}, time.Second},
+		{midi.Message{Status: 0x80, Data: [2]byte{0x3c, 0x00}}, 0},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("got %d actions, want %d: %v", len(actions), len(want), actions)
+	}
+	for i, w := range want {
+		if actions[i].Value != w.msg || actions[i].Duration != w.d {
+			t.Errorf("action %d: got %v for %s, want %v for %s", i, actions[i].Value, actions[i].Duration, w.msg, w.d)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := midi.NewWriter(&buf)
+	for _, m := range []midi.Message{
+		{Status: 0x90, Data: [2]byte{0x3c, 0x64}},
+		{}, // Rest.
+		{Status: 0x90, Data: [2]byte{0x40, 0x64}},
+		{Status: 0xc0, Data: [2]byte{0x05}},
+		{Status: 0x90, Data: [2]byte{0x3c, 0x00}},
+	} {
+		if err := w.WriteMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []byte{0x90, 0x3c, 0x64, 0x40, 0x64, 0xc0, 0x05, 0x90, 0x3c, 0x00}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got % x, want % x", buf.Bytes(), want)
+	}
+}
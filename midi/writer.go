@@ -0,0 +1,44 @@
+package midi
+
+import "io"
+
+// Writer sends messages to a MIDI port such as a serial device or USB MIDI
+// endpoint. It uses running status: the status byte is omitted when it equals
+// that of the previous channel message, reducing bandwidth on 31250 baud links.
+type Writer struct {
+	w      io.Writer
+	status byte
+	buf    [3]byte
+}
+
+// NewWriter returns a Writer that sends messages to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteMessage sends m. Rests are not sent. Typically called with the values
+// returned by ScheduleNext when ok is true.
+func (w *Writer) WriteMessage(m Message) error {
+	if m.IsRest() {
+		return nil
+	}
+	buf := w.buf[:0]
+	if m.Status != w.status {
+		buf = append(buf, m.Status)
+	}
+	buf = append(buf, m.Data[:m.Len()]...)
+	_, err := w.w.Write(buf)
+	if err != nil {
+		w.status = 0 // Receiver state is unknown, resend status next time.
+		return err
+	}
+	w.status = m.Status
+	return nil
+}
+
+// Reset makes the next message be sent with its status byte. It should be
+// called when the receiver may have lost track of the running status,
+// for example after reconnecting.
+func (w *Writer) Reset() {
+	w.status = 0
+}
@@ -0,0 +1,9 @@
+//go:build !schedule_rt
+
+package schedule
+
+// rtChecks enables real-time assertions in the hot path. It is set by the
+// schedule_rt build tag.
+const rtChecks = false
+
+func rtAssert(cond bool, msg string) {}
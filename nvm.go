@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// nvmRecordSize is the size of a progress record: sequence number, position and checksum.
+const nvmRecordSize = 16
+
+var errNVMSlots = errors.New("NVMProgress requires at least one slot")
+
+// NVM is small non-volatile storage such as EEPROM or flash addressed by byte offset.
+type NVM interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// ProgressGroup is implemented by groups whose progress can be persisted
+// with NVMProgress. GroupSync implements ProgressGroup.
+type ProgressGroup interface {
+	progress() int
+	setProgress(lastPos int)
+}
+
+var _ ProgressGroup = (*GroupSync[int])(nil)
+
+// NVMProgress persists the progress of a group, the position of the last
+// scheduled action, to small non-volatile storage so battery-backed devices
+// survive brown-outs mid-schedule. Unlike Snapshot only the position is stored:
+// the group's start time must be recoverable by other means, such as an RTC.
+//
+// Records have a fixed size of 16 bytes and are written round robin over
+// a number of slots to spread wear. Each record carries a sequence number and
+// checksum so a torn write during power loss falls back to the previous record.
+type NVMProgress struct {
+	dev   NVM
+	slots int
+	// seq is the sequence number of the last record and slot its slot.
+	seq  uint32
+	slot int
+	// saved is the last position written, valid if hasSaved is true.
+	saved    int
+	hasSaved bool
+}
+
+// NewNVMProgress returns an NVMProgress that uses slots records of storage
+// starting at offset zero of dev. Existing records are scanned so writing
+// continues after the most recent valid record.
+func NewNVMProgress(dev NVM, slots int) (*NVMProgress, error) {
+	if slots <= 0 {
+		return nil, errNVMSlots
+	}
+	p := &NVMProgress{dev: dev, slots: slots, slot: -1}
+	var buf [nvmRecordSize]byte
+	for i := 0; i < slots; i++ {
+		if _, err := dev.ReadAt(buf[:], int64(i*nvmRecordSize)); err != nil {
+			return nil, err
+		}
+		seq, pos, ok := decodeNVMRecord(buf)
+		// Compare sequence numbers with wraparound.
+		if ok && (!p.hasSaved || int32(seq-p.seq) > 0) {
+			p.seq, p.slot, p.saved, p.hasSaved = seq, i, pos, true
+		}
+	}
+	return p, nil
+}
+
+// Save writes the group's progress if it changed since the last save.
+// It should be called after ScheduleNext schedules an action.
+func (p *NVMProgress) Save(g ProgressGroup) error {
+	pos := g.progress()
+	if p.hasSaved && pos == p.saved {
+		return nil // Avoid wearing storage with redundant writes.
+	}
+	seq, slot := p.seq+1, (p.slot+1)%p.slots
+	buf := encodeNVMRecord(seq, pos)
+	if _, err := p.dev.WriteAt(buf[:], int64(slot*nvmRecordSize)); err != nil {
+		return err
+	}
+	p.seq, p.slot, p.saved, p.hasSaved = seq, slot, pos, true
+	return nil
+}
+
+// Restore sets the group's progress to the last saved position. It must be
+// called after Begin. ok is false if no valid record was found.
+func (p *NVMProgress) Restore(g ProgressGroup) (ok bool) {
+	if !p.hasSaved {
+		return false
+	}
+	g.setProgress(p.saved)
+	return true
+}
+
+func encodeNVMRecord(seq uint32, pos int) (buf [nvmRecordSize]byte) {
+	binary.LittleEndian.PutUint32(buf[0:], seq)
+	binary.LittleEndian.PutUint64(buf[4:], uint64(int64(pos)))
+	binary.LittleEndian.PutUint32(buf[12:], crc32.ChecksumIEEE(buf[:12]))
+	return buf
+}
+
+func decodeNVMRecord(buf [nvmRecordSize]byte) (seq uint32, pos int, ok bool) {
+	if crc32.ChecksumIEEE(buf[:12]) != binary.LittleEndian.Uint32(buf[12:]) {
+		return 0, 0, false
+	}
+	pos = int(int64(binary.LittleEndian.Uint64(buf[4:])))
+	return binary.LittleEndian.Uint32(buf[0:]), pos, pos >= -1
+}
+
+func (g *GroupSync[T]) progress() int { return g.lastPos }
+
+func (g *GroupSync[T]) setProgress(lastPos int) { g.lastPos = lastPos }
@@ -0,0 +1,231 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses a textual schedule description such as
+//
+//	every 5m run pump for 30s then rest 4m30s x6
+//
+// into actions whose values are the names of the steps and the number of
+// iterations, suitable for NewGroupSync. The grammar is forgiving:
+//
+//   - Steps are separated by "then", "and" or commas.
+//   - A step is "[run|turn on] NAME for DURATION" or "rest|wait|pause DURATION".
+//     Rest steps have an empty name.
+//   - "every DURATION" at the start sets the iteration period. The last step
+//     is followed by a rest lasting the remainder of the period.
+//   - "xN" or "N times" at the end sets the iterations, "forever" sets infinite
+//     iterations. The default is a single iteration.
+//
+// Durations use the time.ParseDuration format. Words are case insensitive.
+func Parse(s string) (actions []Action[string], iterations int, err error) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(s, ",", " then ")))
+	iterations = 1
+	// Parse iteration suffix.
+	if n := len(words); n > 0 {
+		last := words[n-1]
+		switch {
+		case last == "forever":
+			iterations, words = -1, words[:n-1]
+		case last == "times" && n > 1:
+			iterations, err = strconv.Atoi(words[n-2])
+			words = words[:n-2]
+		case strings.HasPrefix(last, "x"):
+			iterations, err = strconv.Atoi(last[1:])
+			words = words[:n-1]
+		}
+		if err != nil || iterations == 0 || iterations < -1 {
+			return nil, 0, fmt.Errorf("invalid iteration count in %q", s)
+		}
+	}
+	var period time.Duration
+	if len(words) >= 2 && words[0] == "every" {
+		period, err = time.ParseDuration(words[1])
+		if err != nil {
+			return nil, 0, err
+		}
+		if period <= 0 {
+			return nil, 0, fmt.Errorf("non-positive period in %q", s)
+		}
+		words = words[2:]
+	}
+	for len(words) > 0 {
+		var step []string
+		step, words = splitStep(words)
+		action, err := parseStep(step)
+		if err != nil {
+			return nil, 0, err
+		}
+		actions = append(actions, action)
+	}
+	if len(actions) == 0 {
+		return nil, 0, errEmptyActions
+	}
+	if period > 0 {
+		total, err := actionsDuration(actions)
+		if err != nil && total == 0 {
+			return nil, 0, err
+		}
+		if total > period {
+			return nil, 0, fmt.Errorf("steps last %s, longer than period of %s", total, period)
+		}
+		if total < period {
+			actions = append(actions, Action[string]{Duration: period - total})
+		}
+	}
+	return actions, iterations, nil
+}
+
+// splitStep returns the words of the first step and the words following its separator.
+func splitStep(words []string) (step, rest []string) {
+	for i, w := range words {
+		if w == "then" || w == "and" {
+			return words[:i], words[i+1:]
+		}
+	}
+	return words, nil
+}
+
+func parseStep(words []string) (Action[string], error) {
+	if len(words) == 0 {
+		return Action[string]{}, fmt.Errorf("empty step")
+	}
+	switch words[0] {
+	case "rest", "wait", "pause":
+		if len(words) == 3 && words[1] == "for" {
+			words = words[1:]
+		}
+		if len(words) != 2 {
+			return Action[string]{}, fmt.Errorf("invalid rest step %q", strings.Join(words, " "))
+		}
+		d, err := parseStepDuration(words[1])
+		return Action[string]{Duration: d}, err
+	case "run":
+		words = words[1:]
+	case "turn":
+		if len(words) > 1 && words[1] == "on" {
+			words = words[2:]
+		}
+	}
+	n := len(words)
+	if n < 3 || words[n-2] != "for" {
+		return Action[string]{}, fmt.Errorf("invalid step %q: expected NAME for DURATION", strings.Join(words, " "))
+	}
+	d, err := parseStepDuration(words[n-1])
+	return Action[string]{Duration: d, Value: strings.Join(words[:n-2], " ")}, err
+}
+
+// parseStepDuration parses the duration of a step, which must not be negative.
+func parseStepDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil && d < 0 {
+		err = fmt.Errorf("%w %q", errNegativeDuration, s)
+	}
+	return d, err
+}
+
+// ParseRepeatingInterval parses an ISO 8601 repeating interval such as
+//
+//	R5/2024-01-01T08:00:00Z/PT2H
+//
+// and returns an equivalent group that schedules value once per interval,
+// and the start time of the first interval to pass to Begin. The forms
+// R[n]/start/duration, R[n]/start/end, R[n]/duration/end and R[n]/duration
+// are accepted. The last form has no start time and the zero time is returned.
+// An omitted repetition count, or -1, means infinite repetitions. Times use the
+// RFC 3339 format. Durations with years or months are rejected since their length varies.
+func ParseRepeatingInterval[T any](s string, value T) (g *GroupSync[T], start time.Time, err error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || !strings.HasPrefix(parts[0], "R") {
+		return nil, start, fmt.Errorf("invalid repeating interval %q", s)
+	}
+	iterations := -1
+	if n := parts[0][1:]; n != "" {
+		iterations, err = strconv.Atoi(n)
+		if err != nil || iterations == 0 || iterations < -1 {
+			return nil, start, fmt.Errorf("invalid repetitions in %q", s)
+		}
+	}
+	var period time.Duration
+	var end time.Time
+	for i, part := range parts[1:] {
+		if strings.HasPrefix(part, "P") {
+			period, err = parseISODuration(part)
+		} else if i == 0 {
+			start, err = time.Parse(time.RFC3339, part)
+		} else {
+			end, err = time.Parse(time.RFC3339, part)
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	if !start.IsZero() && !end.IsZero() {
+		if !end.After(start) {
+			return nil, time.Time{}, fmt.Errorf("interval end not after start in %q", s)
+		}
+		period = end.Sub(start)
+		if period == maxDuration {
+			return nil, time.Time{}, fmt.Errorf("%w: interval in %q", ErrDurationOverflow, s)
+		}
+	}
+	if period <= 0 {
+		return nil, time.Time{}, fmt.Errorf("non-positive interval in %q", s)
+	}
+	if start.IsZero() && !end.IsZero() {
+		if iterations == -1 {
+			return nil, start, fmt.Errorf("infinite repeating interval %q can't end at a time", s)
+		}
+		total, err := iterationsDuration(period, iterations)
+		if err != nil {
+			return nil, start, err
+		}
+		start = end.Add(-total)
+	}
+	g, err = NewGroupSync([]Action[T]{{Duration: period, Value: value}}, GroupSyncConfig{Iterations: iterations})
+	return g, start, err
+}
+
+// parseISODuration parses an ISO 8601 duration with weeks, days, hours,
+// minutes and seconds, such as P1DT2H30M or PT0.5S.
+func parseISODuration(s string) (d time.Duration, err error) {
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	rest := s[1:]
+	if rest == "" || rest == "T" {
+		return 0, fmt.Errorf("empty duration %q", s)
+	}
+	inTime := false
+	for len(rest) > 0 {
+		if rest[0] == 'T' && !inTime {
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return r != '.' && r != ',' && (r < '0' || r > '9') })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		v, err := strconv.ParseFloat(strings.Replace(rest[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, err
+		}
+		unit, ok := units[rest[i]]
+		if inTime {
+			unit, ok = timeUnits[rest[i]]
+		}
+		if !ok {
+			return 0, fmt.Errorf("unsupported unit %q in duration %q", rest[i], s)
+		}
+		if v*float64(unit) >= float64(maxDuration-d) {
+			return 0, fmt.Errorf("%w: %q", ErrDurationOverflow, s)
+		}
+		d += time.Duration(v * float64(unit))
+		rest = rest[i+1:]
+	}
+	return d, nil
+}
@@ -0,0 +1,89 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestParse(t *testing.T) {
+	type action = schedule.Action[string]
+	for _, test := range []struct {
+		s          string
+		want       []action
+		iterations int
+	}{
+		{
+			s:          "every 5m run pump for 30s then rest 4m30s x6",
+			want:       []action{{Duration: 30 * time.Second, Value: "pump"}, {Duration: 4*time.Minute + 30*time.Second}},
+			iterations: 6,
+		},
+		{
+			s:          "every 1h Turn on fan for 10m, heater for 5m",
+			want:       []action{{Duration: 10 * time.Minute, Value: "fan"}, {Duration: 5 * time.Minute, Value: "heater"}, {Duration: 45 * time.Minute}},
+			iterations: 1,
+		},
+		{
+			s:          "run main valve for 1s and wait for 2s forever",
+			want:       []action{{Duration: time.Second, Value: "main valve"}, {Duration: 2 * time.Second}},
+			iterations: -1,
+		},
+		{
+			s:          "led for 100ms then pause 900ms 3 times",
+			want:       []action{{Duration: 100 * time.Millisecond, Value: "led"}, {Duration: 900 * time.Millisecond}},
+			iterations: 3,
+		},
+	} {
+		got, iterations, err := schedule.Parse(test.s)
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if iterations != test.iterations || len(got) != len(test.want) {
+			t.Errorf("%q: got %v x%d, want %v x%d", test.s, got, iterations, test.want, test.iterations)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q: action %d got %v, want %v", test.s, i, got[i], test.want[i])
+			}
+		}
+	}
+	for _, bad := range []string{"", "every 1m pump for 2m", "pump 30s", "rest", "pump for 1s x0", "pump for -1s", "rest -2s", "every -5m pump for 1s"} {
+		if _, _, err := schedule.Parse(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestParseRepeatingInterval(t *testing.T) {
+	want := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		s          string
+		start      time.Time
+		period     time.Duration
+		iterations int
+	}{
+		{"R5/2024-01-01T08:00:00Z/PT2H", want, 2 * time.Hour, 5},
+		{"R2/2024-01-01T08:00:00Z/2024-01-02T09:30:00Z", want, 25*time.Hour + 30*time.Minute, 2},
+		{"R4/PT30M/2024-01-01T10:00:00Z", want, 30 * time.Minute, 4},
+		{"R/P1W", time.Time{}, 7 * 24 * time.Hour, -1},
+		{"R-1/P1DT1.5S", time.Time{}, 24*time.Hour + 1500*time.Millisecond, -1},
+	} {
+		g, start, err := schedule.ParseRepeatingInterval(test.s, 1)
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if !start.Equal(test.start) || g.Duration() != test.period || g.Iterations() != test.iterations {
+			t.Errorf("%q: got start %s period %s iterations %d, want %s %s %d", test.s, start, g.Duration(), g.Iterations(), test.start, test.period, test.iterations)
+		}
+	}
+	for _, bad := range []string{"R5", "5/2024-01-01T08:00:00Z/PT2H", "R0/PT1H", "R/P1M", "R/PT1H/2024-01-01T10:00:00Z", "R2/PT",
+		"R2/2024-01-02T08:00:00Z/2024-01-01T08:00:00Z", "R9223372036854775807/PT1H/2024-01-01T10:00:00Z", "R/PT99999999999999H"} {
+		if _, _, err := schedule.ParseRepeatingInterval(bad, 1); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	errBadSnapshot = errors.New("invalid snapshot")
+	errBadStoreKey = errors.New("store key must be a file name")
+)
+
+// snapshotVersion is the version of the Snapshot binary encoding.
+const snapshotVersion = 1
+
+// Snapshot encoding flags.
+const (
+	snapshotFailed = 1 << iota
+	snapshotHasStop
+)
+
+// Snapshot is the persistable state of a GroupSync. It allows a group to
+// survive restarts of the process driving it. See GroupSync.Checkpoint.
+type Snapshot struct {
+	Start time.Time
+	// ElapsedToRestart and RestartIter locate the start of the current iteration.
+	ElapsedToRestart time.Duration
+	RestartIter      int
+	// LastPos is the position of the last scheduled action counting the
+	// actions of previous iterations. It is -1 if none was scheduled.
+	LastPos    int
+	Iterations int
+	Failed     bool
+	// Stop is the time at which the group reports done. Zero value means no stop.
+	Stop time.Time
+}
+
+// Checkpoint returns the state of the group so it can be restored with Restore.
+func (g *GroupSync[T]) Checkpoint() Snapshot {
+	return Snapshot{
+		Start:            g.start,
+		ElapsedToRestart: g.elapsedToRestart,
+		RestartIter:      g.restartIter,
+		LastPos:          g.lastPos,
+		Iterations:       g.iterations,
+		Failed:           g.failed,
+		Stop:             g.stop,
+	}
+}
+
+// Restore sets the state of the group to that of a snapshot taken with
+// Checkpoint on a group with the same actions. It replaces calling Begin.
+// Actions missed while the group was not running are handled per the group's
+// MissPolicy on the next call to ScheduleNext.
+func (g *GroupSync[T]) Restore(s Snapshot) error {
+	if s.Start.IsZero() || s.LastPos < -1 || s.RestartIter < 0 ||
+		(s.Iterations <= 0 && s.Iterations != -1) {
+		return errBadSnapshot
+	}
+	g.iterations = s.Iterations
+	g.Begin(s.Start)
+	g.start = s.Start // Undo discipline and alignment, snapshot times are final.
+	g.elapsedToRestart = s.ElapsedToRestart
+	g.restartIter = s.RestartIter
+	g.lastPos = s.LastPos
+	g.failed = s.Failed
+	g.stop = s.Stop
+	return nil
+}
+
+// MarshalBinary encodes the snapshot in a compact binary format.
+func (s Snapshot) MarshalBinary() ([]byte, error) {
+	var flags, stop int64
+	if s.Failed {
+		flags |= snapshotFailed
+	}
+	if !s.Stop.IsZero() {
+		flags |= snapshotHasStop
+		stop = s.Stop.UnixNano()
+	}
+	fields := [...]int64{s.Start.UnixNano(), int64(s.ElapsedToRestart), int64(s.RestartIter), int64(s.LastPos), int64(s.Iterations), flags, stop}
+	b := make([]byte, 1+len(fields)*binary.MaxVarintLen64)
+	b[0] = snapshotVersion
+	n := 1
+	for _, v := range fields {
+		n += binary.PutVarint(b[n:], v)
+	}
+	return b[:n], nil
+}
+
+// UnmarshalBinary decodes a snapshot encoded with MarshalBinary.
+func (s *Snapshot) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != snapshotVersion {
+		return errBadSnapshot
+	}
+	b = b[1:]
+	var fields [7]int64
+	for i := range fields {
+		var n int
+		fields[i], n = binary.Varint(b)
+		if n <= 0 {
+			return errBadSnapshot
+		}
+		b = b[n:]
+	}
+	s.Start = time.Unix(0, fields[0])
+	s.ElapsedToRestart = time.Duration(fields[1])
+	s.RestartIter = int(fields[2])
+	s.LastPos = int(fields[3])
+	s.Iterations = int(fields[4])
+	flags := fields[5]
+	s.Failed = flags&snapshotFailed != 0
+	s.Stop = time.Time{}
+	if flags&snapshotHasStop != 0 {
+		s.Stop = time.Unix(0, fields[6])
+	}
+	return nil
+}
+
+// Store is a key-value persistence backend for snapshots such as files,
+// bolt or badger databases. Get must return an error wrapping
+// os.ErrNotExist if key is not present.
+type Store interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// CheckpointTo stores the group's snapshot in st under key.
+func (g *GroupSync[T]) CheckpointTo(st Store, key string) error {
+	b, err := g.Checkpoint().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return st.Put(key, b)
+}
+
+// RestoreFrom restores the group's state from the snapshot stored in st under key.
+func (g *GroupSync[T]) RestoreFrom(st Store, key string) error {
+	b, err := st.Get(key)
+	if err != nil {
+		return err
+	}
+	var s Snapshot
+	if err = s.UnmarshalBinary(b); err != nil {
+		return fmt.Errorf("restoring %q: %w", key, err)
+	}
+	return g.Restore(s)
+}
+
+// FileStore is a Store that keeps each key in a file in directory Dir.
+// Files are replaced atomically so a crash during Put never leaves a
+// partially written snapshot. Keys must be file names: keys containing
+// path separators or ".." are rejected so they cannot refer to files outside Dir.
+type FileStore struct {
+	Dir string
+}
+
+// path returns the path of the file named key.
+func (fs FileStore) path(key string) (string, error) {
+	if key == "" || key == "." || strings.Contains(key, "..") ||
+		strings.ContainsRune(key, '/') || strings.ContainsRune(key, filepath.Separator) {
+		return "", fmt.Errorf("%w: %q", errBadStoreKey, key)
+	}
+	return filepath.Join(fs.Dir, key), nil
+}
+
+// Put writes data to the file named key.
+func (fs FileStore) Put(key string, data []byte) error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(fs.Dir, "."+key+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Get reads the file named key.
+func (fs FileStore) Get(key string) ([]byte, error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,120 @@
+package schedule_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	cfg := schedule.GroupSyncConfig{Iterations: -1}
+	g, err := schedule.NewGroupSync(actions, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for now := start; now.Before(start.Add(7 * time.Second)); now = now.Add(500 * time.Millisecond) {
+		if _, _, _, err = g.ScheduleNext(now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.StopAt(start.Add(time.Hour))
+	store := schedule.FileStore{Dir: t.TempDir()}
+	if err = g.CheckpointTo(store, "pump"); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := schedule.NewGroupSync(actions, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = restored.RestoreFrom(store, "pump"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := restored.Checkpoint(), g.Checkpoint(); got != want {
+		t.Fatalf("got restored snapshot %+v, want %+v", got, want)
+	}
+	// Restored group continues where the original left off: second action of iteration 2.
+	v, ok, next, err := restored.ScheduleNext(start.Add(7 * time.Second))
+	if err != nil || !ok || v != 2 || next != time.Second {
+		t.Errorf("got (%d, %t, %s, %v) after restore, want (2, true, 1s, nil)", v, ok, next, err)
+	}
+	if err = restored.RestoreFrom(store, "missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v for missing key, want os.ErrNotExist", err)
+	}
+	var s schedule.Snapshot
+	if err = s.UnmarshalBinary([]byte{1, 2}); err == nil {
+		t.Error("expected error decoding truncated snapshot")
+	}
+	// A stop at the Unix epoch is not confused with no stop.
+	want := g.Checkpoint()
+	want.Stop = time.Unix(0, 0)
+	b, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.UnmarshalBinary(b); err != nil || s != want {
+		t.Errorf("got %+v, %v decoding snapshot, want %+v", s, err, want)
+	}
+	for _, key := range []string{"../pump", "a/b", "..", ""} {
+		if _, err = store.Get(key); err == nil || errors.Is(err, os.ErrNotExist) {
+			t.Errorf("key %q: got %v, want invalid key error", key, err)
+		}
+		if err = store.Put(key, b); err == nil {
+			t.Errorf("key %q: expected invalid key error", key)
+		}
+	}
+}
+
+// eeprom is an in-memory NVM.
+type eeprom []byte
+
+func (e eeprom) ReadAt(p []byte, off int64) (int, error)  { return copy(p, e[off:]), nil }
+func (e eeprom) WriteAt(p []byte, off int64) (int, error) { return copy(e[off:], p), nil }
+
+func TestNVMProgress(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := make(eeprom, 4*16)
+	p, err := schedule.NewNVMProgress(mem, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	if p.Restore(g) {
+		t.Fatal("expected no progress in blank storage")
+	}
+	for now := start; now.Before(start.Add(5 * time.Second)); now = now.Add(500 * time.Millisecond) {
+		if _, _, _, err = g.ScheduleNext(now); err != nil {
+			t.Fatal(err)
+		}
+		if err = p.Save(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Brown-out: a torn write corrupts the most recent record.
+	copy(mem[0:], []byte{0xde, 0xad})
+	p, err = schedule.NewNVMProgress(mem, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	if !p.Restore(g) {
+		t.Fatal("expected progress to be restored")
+	}
+	// Records for positions 0..4 were written to slots 0,1,2,3,0. Slot 0 is corrupt
+	// so position 3 is restored and action 1 at position 4 is scheduled.
+	v, ok, _, err := g.ScheduleNext(start.Add(4500 * time.Millisecond))
+	if err != nil || !ok || v != 1 {
+		t.Errorf("got (%d, %t, %v) after restore, want (1, true, nil)", v, ok, err)
+	}
+}
@@ -0,0 +1,172 @@
+package schedule
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Branch identifies the decision taken by a ScheduleNext call. Branches
+// correspond to the possible outcomes of GroupSync.ScheduleNext in order of evaluation.
+type Branch uint8
+
+const (
+	// BranchNotBegun is taken when ScheduleNext is called before Begin.
+	BranchNotBegun Branch = iota
+	// BranchFailed is taken when the group failed on a previous call.
+	BranchFailed
+	// BranchAnchorInvalid is taken when the anchored actions could not be laid out from the start time.
+	BranchAnchorInvalid
+	// BranchStopped is taken once the stop time is reached.
+	BranchStopped
+	// BranchBeforeStart is taken before the group's start time.
+	BranchBeforeStart
+	// BranchStoppedOnBoundary is taken when the action due starts after the stop time
+	// of a group configured with StopOnBoundary.
+	BranchStoppedOnBoundary
+	// BranchWaiting is taken while the last scheduled action is still running.
+	BranchWaiting
+	// BranchZeroDuration is taken when a zero duration action is scheduled.
+	BranchZeroDuration
+	// BranchReplayed is taken when a missed action is replayed. See MissReplay.
+	BranchReplayed
+	// BranchDone is taken once all iterations ran.
+	BranchDone
+	// BranchMissed is taken when actions were missed between calls.
+	BranchMissed
+	// BranchScheduled is taken when the action due is scheduled on time.
+	BranchScheduled
+	// BranchInjected is taken when an injected action is scheduled.
+	BranchInjected
+	// BranchInterrupted is taken while an interrupt is pending or running.
+	BranchInterrupted
+	// BranchTolerated is taken when an action is scheduled late within its tolerance.
+	BranchTolerated
+	// BranchGuarded is taken when an action is skipped or held by its guard.
+	BranchGuarded
+)
+
+func (b Branch) String() string {
+	switch b {
+	case BranchNotBegun:
+		return "not begun"
+	case BranchFailed:
+		return "failed"
+	case BranchAnchorInvalid:
+		return "invalid anchor"
+	case BranchStopped:
+		return "stopped"
+	case BranchBeforeStart:
+		return "before start"
+	case BranchStoppedOnBoundary:
+		return "stopped on boundary"
+	case BranchWaiting:
+		return "waiting"
+	case BranchZeroDuration:
+		return "zero duration"
+	case BranchReplayed:
+		return "replayed"
+	case BranchDone:
+		return "done"
+	case BranchMissed:
+		return "missed"
+	case BranchScheduled:
+		return "scheduled"
+	case BranchInjected:
+		return "injected"
+	case BranchInterrupted:
+		return "interrupted"
+	case BranchTolerated:
+		return "tolerated"
+	case BranchGuarded:
+		return "guarded"
+	}
+	return fmt.Sprintf("Branch(%d)", uint8(b))
+}
+
+// Decision is the internal decision of a single ScheduleNext call.
+type Decision struct {
+	// Now is the time passed to ScheduleNext after discipline correction.
+	Now time.Time
+	// Elapsed is the time elapsed since the group's start time.
+	Elapsed time.Duration
+	// Track is the index of the GroupParallel track that took the decision.
+	// It is zero for other groups.
+	Track int
+	// LastPos is the position of the last scheduled action before the call,
+	// counting the actions of previous iterations. It is -1 if none was scheduled.
+	LastPos int
+	// Pos is the position of the action computed to be running at Now.
+	// It is -1 if the call returned before computing it.
+	Pos    int
+	Branch Branch
+	// Value is the formatted value of the scheduled action if Ok is true.
+	// Values implementing fmt.Stringer use their string form.
+	Value string
+	Ok    bool
+	Next  time.Duration
+	Err   error
+}
+
+// Recorder captures the decisions of the most recent ScheduleNext calls of a
+// group in a bounded buffer. When a group fails the recorded decisions can be
+// dumped to report missed action bugs with full context. See GroupSyncConfig.Recorder.
+// A Recorder must not be shared between groups.
+type Recorder struct {
+	buf  []Decision
+	next int
+	full bool
+	// pending holds the branch and position of the call in progress.
+	pending Decision
+}
+
+// NewRecorder returns a recorder that keeps the last capacity decisions.
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Recorder{buf: make([]Decision, capacity)}
+}
+
+// Decisions returns a copy of the recorded decisions, oldest first.
+func (r *Recorder) Decisions() []Decision {
+	if !r.full {
+		return append([]Decision(nil), r.buf[:r.next]...)
+	}
+	return append(append([]Decision(nil), r.buf[r.next:]...), r.buf[:r.next]...)
+}
+
+// Reset discards all recorded decisions.
+func (r *Recorder) Reset() {
+	r.next = 0
+	r.full = false
+}
+
+// Dump writes the recorded decisions to w, oldest first, one per line.
+func (r *Recorder) Dump(w io.Writer) error {
+	for _, d := range r.Decisions() {
+		_, err := fmt.Fprintf(w, "%s elapsed=%s track=%d lastPos=%d pos=%d branch=%q ok=%t value=%q next=%s err=%v\n",
+			d.Now.Format(time.RFC3339Nano), d.Elapsed, d.Track, d.LastPos, d.Pos, d.Branch, d.Ok, d.Value, d.Next, d.Err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) record(d Decision) {
+	r.buf[r.next] = d
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// note sets the branch taken and position computed by the call in progress.
+// It is safe to call on a nil Recorder.
+func (r *Recorder) note(b Branch, pos int) {
+	if r != nil {
+		r.pending.Branch, r.pending.Pos = b, pos
+	}
+}
@@ -0,0 +1,56 @@
+package schedule_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestRecorder(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	rec := schedule.NewRecorder(3)
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Recorder: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.ScheduleNext(start) // Not begun.
+	if d := rec.Decisions()[0]; d.Branch != schedule.BranchNotBegun || d.Pos != -1 {
+		t.Errorf("got branch %q pos=%d before Begin, want %q pos=-1", d.Branch, d.Pos, schedule.BranchNotBegun)
+	}
+	g.Begin(start)
+	for _, elapsed := range []time.Duration{0, 500 * time.Millisecond, 2500 * time.Millisecond} {
+		g.ScheduleNext(start.Add(elapsed))
+	}
+	decisions := rec.Decisions()
+	if len(decisions) != 3 {
+		t.Fatalf("got %d decisions, want capacity 3", len(decisions))
+	}
+	want := []struct {
+		branch       schedule.Branch
+		lastPos, pos int
+	}{
+		{schedule.BranchScheduled, -1, 0},
+		{schedule.BranchWaiting, 0, 0},
+		{schedule.BranchMissed, 0, 2},
+	}
+	for i, w := range want {
+		d := decisions[i]
+		if d.Branch != w.branch || d.LastPos != w.lastPos || d.Pos != w.pos {
+			t.Errorf("decision %d: got branch %q lastPos=%d pos=%d, want %q lastPos=%d pos=%d",
+				i, d.Branch, d.LastPos, d.Pos, w.branch, w.lastPos, w.pos)
+		}
+	}
+	if decisions[2].Err == nil {
+		t.Error("expected missed action error to be recorded")
+	}
+	var sb strings.Builder
+	if err = rec.Dump(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(sb.String(), "\n"); lines != 3 {
+		t.Errorf("got %d dumped lines, want 3", lines)
+	}
+}
@@ -0,0 +1,143 @@
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+var errBadWidth = errors.New("timeline width must be positive")
+
+// TimelineGroup is implemented by groups whose timeline can be rendered
+// with Timeline: GroupSync, GroupLoose and GroupNested.
+type TimelineGroup interface {
+	Duration() time.Duration
+	// actionSpans returns the start of each action relative to the start of
+	// an iteration along with its duration.
+	actionSpans() (offsets, durations []time.Duration)
+	// timelinePos returns the time elapsed in the iteration running at now.
+	// running is false before the group starts and after it is done.
+	timelinePos(now time.Time) (elapsed time.Duration, running bool)
+}
+
+var (
+	_ TimelineGroup = (*GroupSync[int])(nil)
+	_ TimelineGroup = (*GroupLoose[int])(nil)
+	_ TimelineGroup = (*GroupNested[int])(nil)
+)
+
+// Timeline writes a proportional ASCII timeline of one iteration of g that is
+// width characters wide, with a '|' marker at the position of now within the
+// iteration. Consecutive non-zero duration actions alternate between '#' and '=' characters so
+// action boundaries are visible and idle time before anchored actions is drawn with '.'.
+// The marker follows the group's own timeline, so shifts such as late completions
+// are accounted for. No marker is drawn before the group starts or after it is done.
+//
+// The line starts with a carriage return and has no trailing newline so calling
+// Timeline repeatedly refreshes the timeline in place on terminals and serial consoles.
+func Timeline(w io.Writer, g TimelineGroup, now time.Time, width int) error {
+	if width <= 0 {
+		return errBadWidth
+	}
+	duration := g.Duration()
+	if duration <= 0 {
+		return errZeroDuration
+	}
+	var buf bytes.Buffer
+	buf.Grow(width + 3)
+	buf.WriteString("\r[")
+	offsets, durations := g.actionSpans()
+	idx, shown := -1, -1 // shown counts non-zero duration actions drawn.
+	for col := 0; col < width; col++ {
+		at := time.Duration(float64(duration) * float64(col) / float64(width))
+		for idx+1 < len(offsets) && offsets[idx+1] <= at {
+			idx++
+			if durations[idx] > 0 {
+				shown++
+			}
+		}
+		char := byte('#')
+		switch {
+		case idx < 0 || at >= offsets[idx]+durations[idx]:
+			char = '.' // Waiting for an anchored action.
+		case shown%2 == 1:
+			char = '='
+		}
+		buf.WriteByte(char)
+	}
+	buf.WriteByte(']')
+	if elapsed, running := g.timelinePos(now); running {
+		col := int(float64(elapsed) / float64(duration) * float64(width))
+		if col >= width {
+			col = width - 1
+		}
+		buf.Bytes()[2+col] = '|'
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (g *GroupSync[T]) actionSpans() (offsets, durations []time.Duration) {
+	return g.offsets, durationsOf(g.actions)
+}
+
+func (g *GroupSync[T]) timelinePos(now time.Time) (elapsed time.Duration, running bool) {
+	return g.iterationElapsed(now)
+}
+
+func (g *GroupLoose[T]) actionSpans() (offsets, durations []time.Duration) {
+	return spansOf(durationsOf(g.actions))
+}
+
+func (g *GroupLoose[T]) timelinePos(now time.Time) (elapsed time.Duration, running bool) {
+	stopped := !g.stop.IsZero() && !g.stopOnBoundary && !now.Before(g.stop)
+	if g.start.IsZero() || now.Before(g.start) || g.failed || stopped {
+		return 0, false
+	}
+	if g.lastIdx == -1 {
+		return 0, true
+	}
+	n := len(g.actions)
+	idx := g.lastIdx % n
+	actionElapsed := now.Sub(g.lastActionStart)
+	if actionElapsed >= g.lastDuration {
+		if g.iterations != -1 && g.lastIdx+1 >= n*g.iterations {
+			return 0, false // Done.
+		}
+		actionElapsed = g.lastDuration
+	}
+	if actionElapsed > g.actions[idx].Duration {
+		actionElapsed = g.actions[idx].Duration
+	}
+	for _, a := range g.actions[:idx] {
+		elapsed += a.Duration
+	}
+	return elapsed + actionElapsed, true
+}
+
+func (g *GroupNested[T]) actionSpans() (offsets, durations []time.Duration) {
+	return spansOf(g.actionDurations())
+}
+
+func (g *GroupNested[T]) timelinePos(now time.Time) (elapsed time.Duration, running bool) {
+	if g.start.IsZero() || now.Before(g.start) || g.failed {
+		return 0, false
+	}
+	elapsed = now.Sub(g.start) - g.elapsedToRestart
+	if iter := g.restartIter + int(elapsed/g.duration); g.iterations != -1 && iter >= g.iterations {
+		return 0, false
+	}
+	return elapsed % g.duration, true
+}
+
+// spansOf returns the offsets of back to back actions with the given durations.
+func spansOf(durations []time.Duration) (offsets, _ []time.Duration) {
+	offsets = make([]time.Duration, len(durations))
+	var end time.Duration
+	for i, d := range durations {
+		offsets[i] = end
+		end += d
+	}
+	return offsets, durations
+}
@@ -0,0 +1,99 @@
+package schedule_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+func TestTimeline(t *testing.T) {
+	actions := []actionInt{
+		{Duration: 4 * time.Second, Value: 1},
+		{Duration: 0, Value: 2},
+		{Duration: 2 * time.Second, Value: 3},
+		{Duration: 4 * time.Second, Value: 4},
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	start := time.Unix(100, 0)
+	if err = schedule.Timeline(&sb, g, start, 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\r[####==####]"; got != want {
+		t.Errorf("got %q before start, want %q", got, want)
+	}
+	g.Begin(start)
+	for _, test := range []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, "\r[|###==####]"},
+		{5 * time.Second, "\r[####=|####]"},
+		{16 * time.Second, "\r[####==|###]"}, // Second iteration.
+		{25 * time.Second, "\r[####==####]"}, // Done.
+	} {
+		sb.Reset()
+		if err = schedule.Timeline(&sb, g, start.Add(test.elapsed), 10); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != test.want {
+			t.Errorf("elapsed %s: got %q, want %q", test.elapsed, got, test.want)
+		}
+	}
+	if err = schedule.Timeline(&sb, g, start, 0); err == nil {
+		t.Error("expected error for zero width")
+	}
+
+	// The marker follows the group when a late action is completed.
+	g, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2, CompleteLate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNext(start)
+	g.ScheduleNext(start.Add(5 * time.Second))
+	g.ScheduleNext(start.Add(5 * time.Second))
+	sb.Reset()
+	if err = schedule.Timeline(&sb, g, start.Add(5*time.Second), 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\r[####|=####]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Idle time before anchored actions is drawn.
+	anchored := []actionInt{{Duration: 2 * time.Second, Value: 1}, {At: start.Add(6 * time.Second), Duration: 4 * time.Second, Value: 2}}
+	g, err = schedule.NewGroupSync(anchored, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	sb.Reset()
+	if err = schedule.Timeline(&sb, g, start.Add(3*time.Second), 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\r[##.|..====]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// GroupLoose's marker follows the actions as they are scheduled.
+	gl, err := schedule.NewGroupLoose([]actionInt{{Duration: 4 * time.Second, Value: 1}, {Duration: 6 * time.Second, Value: 2}}, schedule.GroupLooseConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gl.Begin(start)
+	gl.ScheduleNext(start)
+	gl.ScheduleNext(start.Add(6 * time.Second))
+	sb.Reset()
+	if err = schedule.Timeline(&sb, gl, start.Add(8*time.Second), 10); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "\r[####==|===]"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
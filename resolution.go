@@ -0,0 +1,83 @@
+package schedule
+
+import "time"
+
+// Quantize returns a copy of actions with durations rounded to the nearest
+// multiple of resolution, which is useful when targeting an event loop that
+// runs at a fixed period. It also returns the total rounding error
+// (sum of quantized minus original durations) and the worst rounding error
+// of a single action as an absolute value.
+//
+// Actions shorter than half the resolution are rounded down to zero duration.
+// If resolution is not positive the actions are returned unchanged.
+func Quantize[T any](actions []Action[T], resolution time.Duration) (quantized []Action[T], totalErr, worstErr time.Duration) {
+	quantized = make([]Action[T], len(actions))
+	copy(quantized, actions)
+	if resolution <= 0 {
+		return quantized, 0, 0
+	}
+	for i := range quantized {
+		original := quantized[i].Duration
+		quantized[i].Duration = original.Round(resolution)
+		diff := quantized[i].Duration - original
+		totalErr += diff
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > worstErr {
+			worstErr = diff
+		}
+	}
+	return quantized, totalErr, worstErr
+}
+
+// DefaultSafetyFactor is a reasonable safety factor for PollPeriod and TickerFor.
+// Polling at twice the required resolution leaves room for event loop jitter.
+const DefaultSafetyFactor = 2
+
+// Resolver is implemented by groups that can report the maximum interval
+// between ScheduleNext calls they tolerate.
+type Resolver interface {
+	RequiredResolution() time.Duration
+}
+
+// PollPeriod returns the period at which g should be polled, which is the group's
+// required resolution divided by safety. A safety factor less than 1 is treated as 1.
+func PollPeriod(g Resolver, safety float64) time.Duration {
+	if safety < 1 {
+		safety = 1
+	}
+	return time.Duration(float64(g.RequiredResolution()) / safety)
+}
+
+// TickerFor returns a ticker that ticks at PollPeriod(g, safety). Like time.NewTicker
+// it panics if the resulting period is not positive, which is the case for groups
+// consisting only of zero duration actions.
+func TickerFor(g Resolver, safety float64) *time.Ticker {
+	return time.NewTicker(PollPeriod(g, safety))
+}
+
+// Sample is the value V held by a group at time T since the start of an iteration.
+type Sample[T any] struct {
+	T time.Duration
+	V T
+}
+
+// Samples renders a single iteration of the group into evenly spaced samples of
+// the value held at each multiple of resolution, for plotting or for feeding
+// DAC or PWM lookup tables. Zero duration actions are never held and so do not
+// appear in the samples. It returns nil if resolution is not positive.
+func (g *GroupSync[T]) Samples(resolution time.Duration) []Sample[T] {
+	if resolution <= 0 {
+		return nil
+	}
+	samples := make([]Sample[T], 0, (g.duration+resolution-1)/resolution)
+	for t := time.Duration(0); t < g.duration; t += resolution {
+		idx, _ := g.currentIdx(t)
+		if idx < 0 {
+			continue // Idle gap before the first anchored action.
+		}
+		samples = append(samples, Sample[T]{T: t, V: g.actions[idx].Value})
+	}
+	return samples
+}
@@ -0,0 +1,15 @@
+//go:build schedule_rt
+
+package schedule
+
+// rtChecks enables real-time assertions in the hot path. It is set by the
+// schedule_rt build tag.
+const rtChecks = true
+
+// rtAssert panics if cond is false. Assertions check invariants whose
+// violation would make ScheduleNext run for an unbounded time.
+func rtAssert(cond bool, msg string) {
+	if !cond {
+		panic("schedule: real-time assertion failed: " + msg)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build schedule_rt
+
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soypat/schedule"
+)
+
+// TestRealTimeNoAlloc fails if ScheduleNext allocates in the hot path.
+// Run with go test -tags schedule_rt.
+func TestRealTimeNoAlloc(t *testing.T) {
+	g := newBenchGroup(t)
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	now := start
+	allocs := testing.AllocsPerRun(10000, func() {
+		now = now.Add(100 * time.Microsecond)
+		if _, _, _, err := g.ScheduleNext(now); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ScheduleNext allocated %g times per call, want 0", allocs)
+	}
+}
+
+func BenchmarkScheduleNext(b *testing.B) {
+	g := newBenchGroup(b)
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := g.ScheduleNext(start.Add(time.Duration(i) * 100 * time.Microsecond)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { g.ScheduleNext(start) }); allocs != 0 {
+		b.Fatalf("ScheduleNext allocated %g times per call, want 0", allocs)
+	}
+}
+
+func newBenchGroup(tb testing.TB) *schedule.GroupSync[int] {
+	actions := make([]actionInt, 64)
+	for i := range actions {
+		actions[i] = actionInt{Duration: time.Duration(i%4+1) * time.Millisecond, Value: i}
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return g
+}
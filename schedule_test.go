@@ -3,6 +3,7 @@ package schedule_test
 import (
 	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -15,7 +16,7 @@ import (
 type actionInt = schedule.Action[int]
 
 type GroupInt interface {
-	Begins(time.Time)
+	Begin(time.Time)
 	// Expect v to be zero only
 	ScheduleNext(time.Time) (v int, ok bool, next time.Duration, err error)
 	Duration() time.Duration
@@ -23,7 +24,7 @@ type GroupInt interface {
 	StartTime() time.Time
 }
 
-func ExampleGroup() {
+func ExampleGroupSync() {
 	type addAction = schedule.Action[int]
 	actions := []addAction{
 		{Duration: time.Second / 2, Value: 20},
@@ -40,7 +41,7 @@ func ExampleGroup() {
 
 	const resolution = time.Second / 4
 	var sum int
-	g.Begins(time.Now())
+	g.Begin(time.Now())
 	for range time.NewTicker(resolution).C {
 		v, ok, next, err := g.ScheduleNext(time.Now())
 		if err != nil {
@@ -117,12 +118,23 @@ func testGroupCommon(t *testing.T, g GroupInt, actions []actionInt) {
 		}
 		groupDuration += dur
 	}
+	if resolver, ok := g.(interface{ RequiredResolution() time.Duration }); ok {
+		var minD time.Duration
+		for _, action := range actions {
+			if action.Duration > 0 && (minD == 0 || action.Duration < minD) {
+				minD = action.Duration
+			}
+		}
+		if got := resolver.RequiredResolution(); got != minD {
+			t.Errorf("bad RequiredResolution got %d, wanted %d", got, minD)
+		}
+	}
 	if groupDuration != g.Duration() {
 		t.Errorf("bad duration calc got %d, wanted %d", g.Duration(), groupDuration)
 	}
 	var start time.Time
 	start = start.Add(2)
-	g.Begins(start) // Setup group.
+	g.Begin(start) // Setup group.
 	if got := g.StartTime(); !got.Equal(start) {
 		t.Error("bad StartTime result", got, "expected", start)
 	}
@@ -209,6 +221,545 @@ func testGroupCommon(t *testing.T, g GroupInt, actions []actionInt) {
 	}
 }
 
+func TestStopAt(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}}
+	var start time.Time
+	start = start.Add(1)
+	for _, onBoundary := range []bool{false, true} {
+		gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, StopOnBoundary: onBoundary})
+		if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+			t.Fatal(err)
+		}
+		gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1, StopOnBoundary: onBoundary})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, g := range []interface {
+			GroupInt
+			StopAfter(time.Duration)
+		}{gs, gl} {
+			g.Begin(start)
+			g.StopAfter(15)
+			wantStop := time.Duration(15)
+			if onBoundary {
+				wantStop = 20
+			}
+			for elapsed := time.Duration(0); elapsed <= 30; elapsed++ {
+				v, ok, next, err := g.ScheduleNext(start.Add(elapsed))
+				if err != nil {
+					t.Fatal(err)
+				}
+				done := !ok && next == 0
+				if done != (elapsed >= wantStop) {
+					t.Fatalf("%T boundary=%v elapsed=%d: got done=%v", g, onBoundary, elapsed, done)
+				}
+				if !done && elapsed+next > wantStop {
+					t.Errorf("%T boundary=%v elapsed=%d: next=%d exceeds stop", g, onBoundary, elapsed, next)
+				}
+				if ok && v == 3 {
+					t.Errorf("%T boundary=%v: action after stop was scheduled", g, onBoundary)
+				}
+			}
+		}
+	}
+}
+
+func TestFinishIteration(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}}
+	const runtime = 30
+	var start time.Time
+	start = start.Add(1)
+	gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []interface {
+		GroupInt
+		FinishIteration()
+	}{gs, gl} {
+		g.Begin(start)
+		for elapsed := time.Duration(0); elapsed <= 4*runtime; elapsed++ {
+			if elapsed == 2*runtime+15 {
+				g.FinishIteration()
+				if g.Iterations() != 3 {
+					t.Fatalf("%T: got %d iterations after FinishIteration, want 3", g, g.Iterations())
+				}
+			}
+			_, ok, next, err := g.ScheduleNext(start.Add(elapsed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			done := !ok && next == 0
+			if done != (elapsed >= 3*runtime) {
+				t.Fatalf("%T elapsed=%d: got done=%v", g, elapsed, done)
+			}
+		}
+	}
+}
+
+func TestAddIterations(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}}
+	const runtime = 30
+	var start time.Time
+	start = start.Add(1)
+	gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []interface {
+		GroupInt
+		AddIterations(int) error
+	}{gs, gl} {
+		if err := g.AddIterations(0); err == nil {
+			t.Errorf("%T: expected error adding zero iterations", g)
+		}
+		g.Begin(start)
+		var fired int
+		for elapsed := time.Duration(0); elapsed <= 4*runtime; elapsed++ {
+			if elapsed == runtime/2 {
+				if err := g.AddIterations(2); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, ok, next, err := g.ScheduleNext(start.Add(elapsed))
+			if err != nil {
+				t.Fatalf("%T elapsed=%d: %v", g, elapsed, err)
+			}
+			if ok {
+				fired++
+			}
+			done := !ok && next == 0
+			if done != (elapsed >= 3*runtime) {
+				t.Fatalf("%T elapsed=%d: got done=%v", g, elapsed, done)
+			}
+		}
+		if fired != 3*len(actions) {
+			t.Errorf("%T: got %d actions fired, want %d", g, fired, 3*len(actions))
+		}
+	}
+}
+
+func TestIterationsRemaining(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}}
+	const runtime = 20
+	var start time.Time
+	start = start.Add(1)
+	gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 3})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []interface {
+		GroupInt
+		IterationsRemaining(time.Time) int
+	}{gs, gl} {
+		g.Begin(start)
+		if got := g.IterationsRemaining(start.Add(-1)); got != 3 {
+			t.Errorf("%T: got %d iterations remaining before start, want 3", g, got)
+		}
+		for elapsed := time.Duration(0); elapsed <= 3*runtime; elapsed++ {
+			now := start.Add(elapsed)
+			g.ScheduleNext(now)
+			want := 3 - int(elapsed/runtime)
+			if got := g.IterationsRemaining(now); got != want {
+				t.Fatalf("%T elapsed=%d: got %d iterations remaining, want %d", g, elapsed, got, want)
+			}
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	actions := []actionInt{{Duration: 14, Value: 1}, {Duration: 25, Value: 2}, {Duration: 31, Value: 3}}
+	actionsCp := append([]actionInt{}, actions...)
+	got, totalErr, worstErr := schedule.Quantize(actions, 10)
+	want := []actionInt{{Duration: 10, Value: 1}, {Duration: 30, Value: 2}, {Duration: 30, Value: 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !slices.Equal(actions, actionsCp) {
+		t.Error("Quantize modified input actions")
+	}
+	if totalErr != 0 {
+		t.Errorf("got total error %d, want 0", totalErr)
+	}
+	if worstErr != 5 {
+		t.Errorf("got worst error %d, want 5", worstErr)
+	}
+}
+
+func TestDetectUnderPolling(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 20, Value: 2}, {Duration: 10, Value: 3}}
+	var start time.Time
+	start = start.Add(1)
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, DetectUnderPolling: true})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	for _, elapsed := range []time.Duration{-20, 0, 10, 20} {
+		_, _, _, err := g.ScheduleNext(start.Add(elapsed))
+		if err != nil {
+			t.Fatalf("elapsed=%d: unexpected error %v", elapsed, err)
+		}
+	}
+	v, ok, _, err := g.ScheduleNext(start.Add(35))
+	if !errors.Is(err, schedule.ErrUnderPolling) {
+		t.Fatalf("expected under-polling warning, got %v", err)
+	}
+	if !ok || v != 3 {
+		t.Errorf("expected action to be scheduled alongside warning, got ok=%v v=%d", ok, v)
+	}
+}
+
+func TestDetectUnderPollingWaitNext(t *testing.T) {
+	// Sleeping exactly the returned next must not be reported as under-polling
+	// even if it exceeds the required resolution.
+	actions := []actionInt{{Duration: 10 * time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, DetectUnderPolling: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	v, ok, next, err := g.ScheduleNext(start)
+	if err != nil || !ok || v != 1 || next != 10*time.Second {
+		t.Fatalf("got v=%d ok=%v next=%s err=%v", v, ok, next, err)
+	}
+	v, ok, _, err = g.ScheduleNext(start.Add(next))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !ok || v != 2 {
+		t.Errorf("got ok=%v v=%d, want second action", ok, v)
+	}
+}
+
+func TestPollPeriod(t *testing.T) {
+	actions := []actionInt{{Duration: 40 * time.Millisecond, Value: 1}, {Duration: 10 * time.Millisecond, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.PollPeriod(g, schedule.DefaultSafetyFactor); got != 5*time.Millisecond {
+		t.Errorf("got poll period %s, want 5ms", got)
+	}
+	if got := schedule.PollPeriod(g, 0.5); got != 10*time.Millisecond {
+		t.Errorf("got poll period %s for safety factor below 1, want 10ms", got)
+	}
+}
+
+func TestGroupSyncZeroDuration(t *testing.T) {
+	actions := []actionInt{
+		{Duration: 0, Value: 1}, {Duration: 10, Value: 2}, {Duration: 0, Value: 3},
+		{Duration: 0, Value: 4}, {Duration: 10, Value: 5}, {Duration: 0, Value: 6},
+	}
+	var start time.Time
+	start = start.Add(1)
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	var got []int
+	for elapsed := time.Duration(0); elapsed <= 2*g.Duration(); elapsed++ {
+		for {
+			v, ok, next, err := g.ScheduleNext(start.Add(elapsed))
+			if err != nil {
+				t.Fatalf("elapsed=%d: %v", elapsed, err)
+			}
+			if ok {
+				got = append(got, v)
+			}
+			if !ok || next != 0 {
+				break
+			}
+		}
+	}
+	want := []int{1, 2, 3, 4, 5, 6, 1, 2, 3, 4, 5, 6}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	_, err = schedule.NewGroupSync([]actionInt{{Duration: 0, Value: 1}}, schedule.GroupSyncConfig{Iterations: 1})
+	if err == nil {
+		t.Error("expected error for group with zero total duration")
+	}
+}
+
+func TestGroupLooseMaxLate(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}}
+	var start time.Time
+	start = start.Add(1)
+	g, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1, MaxLate: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	for _, elapsed := range []time.Duration{0, 15} {
+		_, ok, _, err := g.ScheduleNext(start.Add(elapsed))
+		if err != nil || !ok {
+			t.Fatalf("elapsed=%d: expected action within lateness, got ok=%v err=%v", elapsed, ok, err)
+		}
+	}
+	_, ok, _, err := g.ScheduleNext(start.Add(31))
+	if err == nil || ok {
+		t.Fatalf("expected lateness error, got ok=%v err=%v", ok, err)
+	}
+	_, _, _, err = g.ScheduleNext(start.Add(32))
+	if err == nil {
+		t.Fatal("expected failed group to keep returning errors")
+	}
+}
+
+func TestGroupLooseCatchUp(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}}
+	var start time.Time
+	start = start.Add(1)
+	g, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1, CatchUp: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	for _, test := range []struct {
+		elapsed  time.Duration
+		wantOK   bool
+		wantNext time.Duration
+	}{
+		{elapsed: 0, wantOK: true, wantNext: 10},
+		{elapsed: 14, wantOK: true, wantNext: 6}, // Late by 4.
+		{elapsed: 19, wantOK: false, wantNext: 1},
+		{elapsed: 20, wantOK: true, wantNext: 10},
+		{elapsed: 30, wantOK: false, wantNext: 0},
+	} {
+		_, ok, next, err := g.ScheduleNext(start.Add(test.elapsed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.wantOK || next != test.wantNext {
+			t.Errorf("elapsed=%d: got ok=%v next=%d, want ok=%v next=%d", test.elapsed, ok, next, test.wantOK, test.wantNext)
+		}
+	}
+}
+
+func TestGroupSyncAnchored(t *testing.T) {
+	var start time.Time
+	start = start.Add(1)
+	actions := []actionInt{
+		{Duration: 10, Value: 1},
+		{Duration: 10, Value: 2, At: start.Add(20)}, // Idle from 10 to 20.
+		{Duration: 10, Value: 3},
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	if g.Duration() != 40 {
+		t.Errorf("got duration %d, want 40", g.Duration())
+	}
+	var fired []int
+	for elapsed := time.Duration(0); elapsed <= 40; elapsed++ {
+		v, ok, next, err := g.ScheduleNext(start.Add(elapsed))
+		if err != nil {
+			t.Fatalf("elapsed=%d: %v", elapsed, err)
+		}
+		if ok {
+			fired = append(fired, v)
+			if wantElapsed := [...]time.Duration{0, 20, 30}[v-1]; elapsed != wantElapsed {
+				t.Errorf("action %d fired at %d, want %d", v, elapsed, wantElapsed)
+			}
+		}
+		if elapsed >= 10 && elapsed < 20 && next != 20-elapsed {
+			t.Errorf("elapsed=%d: got next=%d during idle gap, want %d", elapsed, next, 20-elapsed)
+		}
+	}
+	if !slices.Equal(fired, []int{1, 2, 3}) {
+		t.Errorf("got fired actions %v", fired)
+	}
+
+	g.Begin(start.Add(15)) // Too late for anchor.
+	if _, _, _, err = g.ScheduleNext(start.Add(15)); err == nil {
+		t.Error("expected error when starting after anchored action")
+	}
+	_, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2})
+	if err == nil {
+		t.Error("expected error for anchored actions with multiple iterations")
+	}
+	actions[2].At = start.Add(25) // Overlaps action 2.
+	_, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err == nil {
+		t.Error("expected error for inconsistent anchors")
+	}
+}
+
+func TestGroupSyncReanchor(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}}
+	var start time.Time
+	start = start.Add(1)
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 3, Reanchor: true})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	// Each iteration's first action is scheduled 3 late. Reanchoring delays
+	// the rest of the iteration so the following actions are on time.
+	now := start
+	for iter := 0; iter < 3; iter++ {
+		now = now.Add(3)
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil || !ok || v != 1 || next != 10 {
+			t.Fatalf("iter=%d: got v=%d ok=%v next=%d err=%v", iter, v, ok, next, err)
+		}
+		now = now.Add(next)
+		v, ok, next, err = g.ScheduleNext(now)
+		if err != nil || !ok || v != 2 || next != 10 {
+			t.Fatalf("iter=%d: got v=%d ok=%v next=%d err=%v", iter, v, ok, next, err)
+		}
+		now = now.Add(next)
+	}
+	_, ok, next, err := g.ScheduleNext(now)
+	if err != nil || ok || next != 0 {
+		t.Errorf("expected group done, got ok=%v next=%d err=%v", ok, next, err)
+	}
+	if got := now.Sub(start); got != 69 {
+		t.Errorf("got total runtime %d, want 69", got)
+	}
+}
+
+func TestDurationOverflow(t *testing.T) {
+	actions := []actionInt{{Duration: time.Hour, Value: 1}, {Duration: time.Hour, Value: 2}}
+	const maxIterations = int(math.MaxInt64 / (2 * time.Hour))
+	if _, err := schedule.TotalDuration(actions, maxIterations); err != nil {
+		t.Errorf("unexpected error for largest iteration count: %v", err)
+	}
+	_, err := schedule.TotalDuration(actions, maxIterations+1)
+	if !errors.Is(err, schedule.ErrDurationOverflow) {
+		t.Errorf("expected overflow error, got %v", err)
+	}
+	_, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: maxIterations + 1})
+	if !errors.Is(err, schedule.ErrDurationOverflow) {
+		t.Errorf("expected GroupSync overflow error, got %v", err)
+	}
+	gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: maxIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = gl.AddIterations(1); !errors.Is(err, schedule.ErrDurationOverflow) {
+		t.Errorf("expected GroupLoose overflow error on AddIterations, got %v", err)
+	}
+}
+
+func TestIterationBoundary(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}}
+	var start time.Time
+	start = start.Add(1)
+	for _, reanchor := range []bool{false, true} {
+		g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1, Reanchor: reanchor})
+		if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+			t.Fatal(err)
+		}
+		g.Begin(start)
+		for elapsed := time.Duration(0); elapsed < 100; elapsed++ {
+			if _, _, _, err := g.ScheduleNext(start.Add(elapsed)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for iter := 0; iter < 10; iter++ {
+			got := g.IterationBoundary(iter)
+			want := start.Add(time.Duration(iter) * 20)
+			if reanchor && iter < 4 {
+				want = time.Time{} // Boundaries prior to reanchor are unknown.
+			}
+			if !got.Equal(want) {
+				t.Errorf("reanchor=%v: got boundary %v for iteration %d, want %v", reanchor, got, iter, want)
+			}
+		}
+	}
+}
+
+func TestSyncPulse(t *testing.T) {
+	actions := []actionInt{{Duration: 50, Value: 1}, {Duration: 50, Value: 2}}
+	var start time.Time
+	start = start.Add(1)
+	for _, offset := range []time.Duration{3, -3} {
+		g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1, MaxPulseCorrection: 2})
+		if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+			t.Fatal(err)
+		}
+		g.Begin(start)
+		wantCorrections := []time.Duration{2, 1, 0, 0}
+		if offset < 0 {
+			wantCorrections = []time.Duration{-2, -1, 0, 0}
+		}
+		var pulses int
+		for elapsed := time.Duration(0); elapsed < 500; elapsed++ {
+			now := start.Add(elapsed)
+			if _, _, _, err := g.ScheduleNext(now); err != nil {
+				t.Fatalf("offset=%d elapsed=%d: %v", offset, elapsed, err)
+			}
+			if elapsed%100 == (100+offset)%100 && pulses < len(wantCorrections) {
+				if got := g.SyncPulse(now); got != wantCorrections[pulses] {
+					t.Errorf("offset=%d pulse %d: got correction %d, want %d", offset, pulses, got, wantCorrections[pulses])
+				}
+				pulses++
+			}
+		}
+		if got := g.IterationBoundary(4).Sub(start); got != 400+offset {
+			t.Errorf("offset=%d: got boundary at %d after pulses, want %d", offset, got, 400+offset)
+		}
+	}
+}
+
+func TestResync(t *testing.T) {
+	actions := []actionInt{{Duration: 10, Value: 1}, {Duration: 10, Value: 2}, {Duration: 10, Value: 3}, {Duration: 10, Value: 4}}
+	var start time.Time
+	start = start.Add(1)
+	for _, policy := range []schedule.MissPolicy{schedule.MissFail, schedule.MissSkip} {
+		g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, OnMiss: policy})
+		if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+			t.Fatal(err)
+		}
+		g.Begin(start)
+		now := start.Add(5)
+		if v, ok, _, err := g.ScheduleNext(now); !ok || v != 1 || err != nil {
+			t.Fatalf("got v=%d ok=%v err=%v", v, ok, err)
+		}
+		// Reference is behind: action 2 is delayed.
+		if skipped := g.Resync(now, 2); skipped != 0 {
+			t.Errorf("got %d skipped on backward jump", skipped)
+		}
+		if _, ok, next, _ := g.ScheduleNext(now.Add(4)); ok || next != 4 {
+			t.Errorf("expected action delayed after backward resync, got ok=%v next=%d", ok, next)
+		}
+		// Reference is ahead: action 2 and 3 are skipped.
+		now = now.Add(4)
+		if skipped := g.Resync(now, 35); skipped != 2 {
+			t.Errorf("got %d skipped on forward jump, want 2", skipped)
+		}
+		v, ok, next, err := g.ScheduleNext(now)
+		switch policy {
+		case schedule.MissFail:
+			if err == nil {
+				t.Error("expected missed action error after forward resync")
+			}
+		case schedule.MissSkip:
+			if err != nil || !ok || v != 4 || next != 5 {
+				t.Errorf("expected skip to action 4, got v=%d ok=%v next=%d err=%v", v, ok, next, err)
+			}
+		}
+	}
+}
+
 // returns actions with ordered values 1..n and random durations from minD to maxD.
 // The second parameter returned is the total duration of the actions.
 func randomIntActions(rng *rand.Rand, minD, maxD time.Duration, n int) ([]schedule.Action[int], time.Duration) {
@@ -255,3 +806,680 @@ func currentIdx[T any](actions []schedule.Action[T], elapsed time.Duration) (int
 	}
 	return -1, 0
 }
+
+func TestHarmonic(t *testing.T) {
+	master, err := schedule.NewGroupSync([]actionInt{{Duration: 12, Value: 1}, {Duration: 12, Value: 2}}, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	sub, err := schedule.NewHarmonic(master, []actionInt{{Duration: 3, Value: 10}, {Duration: 5, Value: 20}}, 3)
+	if err != nil && !errors.Is(err, schedule.ErrSmallDuration) {
+		t.Fatal(err)
+	}
+	if _, err = schedule.NewHarmonic(master, []actionInt{{Duration: 5, Value: 10}}, 3); err == nil {
+		t.Error("expected error for non-harmonic period")
+	}
+	var start time.Time
+	start = start.Add(1)
+	for run := 0; run < 2; run++ {
+		master.Begin(start) // Sub group restarts along with master.
+		var fired int
+		for elapsed := time.Duration(0); elapsed <= 48; elapsed++ {
+			now := start.Add(elapsed)
+			if _, _, _, err := master.ScheduleNext(now); err != nil {
+				t.Fatal(err)
+			}
+			v, ok, next, err := sub.ScheduleNext(now)
+			if err != nil {
+				t.Fatalf("elapsed=%d: %v", elapsed, err)
+			}
+			if ok {
+				fired++
+				want := 10
+				if fired%2 == 0 {
+					want = 20
+				}
+				if v != want {
+					t.Errorf("elapsed=%d: got value %d, want %d", elapsed, v, want)
+				}
+			}
+			if done := !ok && next == 0; done != (elapsed == 48) {
+				t.Fatalf("elapsed=%d: got done=%v", elapsed, done)
+			}
+		}
+		if fired != 12 {
+			t.Errorf("got %d harmonic actions fired over two master iterations, want 12", fired)
+		}
+		start = start.Add(100)
+	}
+}
+
+func TestSamples(t *testing.T) {
+	actions := []actionInt{
+		{Duration: 2 * time.Millisecond, Value: 1},
+		{Duration: 0, Value: 2},
+		{Duration: 3 * time.Millisecond, Value: 3},
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := g.Samples(time.Millisecond)
+	want := []int{1, 1, 3, 3, 3}
+	if len(samples) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(samples), len(want))
+	}
+	for i, s := range samples {
+		if s.T != time.Duration(i)*time.Millisecond || s.V != want[i] {
+			t.Errorf("sample %d: got %v at %s, want %d at %s", i, s.V, s.T, want[i], time.Duration(i)*time.Millisecond)
+		}
+	}
+	if g.Samples(0) != nil {
+		t.Error("expected nil samples for zero resolution")
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 0}, {Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1, OnMiss: schedule.MissSkip, MaxDeadLetters: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	g.ScheduleNext(start.Add(2500 * time.Millisecond))
+	if g.Collapsed() != 1 {
+		t.Errorf("got %d collapsed actions, want 1", g.Collapsed())
+	}
+	dl := g.DeadLetters()
+	if len(dl) != 1 || dl[0].Index != 1 || dl[0].Iteration != 0 || dl[0].Value != 1 || !dl[0].Due.Equal(start.Add(time.Second)) {
+		t.Fatalf("got dead letters %+v, want action 1 due 1s after start", dl)
+	}
+	// Skip 6 actions, only most recent 3 are kept.
+	v, _, _, _ := g.ScheduleNext(start.Add(9500 * time.Millisecond))
+	if v != 1 || g.Collapsed() != 6 {
+		t.Errorf("got value %d with %d collapsed actions, want latest value 1 with 6 collapsed", v, g.Collapsed())
+	}
+	dl = g.DeadLetters()
+	if len(dl) != 3 {
+		t.Fatalf("got %d dead letters, want 3", len(dl))
+	}
+	for i, want := range []struct{ idx, iter int }{{2, 1}, {3, 1}, {0, 2}} {
+		due := start.Add(time.Duration(want.iter*4+want.idx) * time.Second)
+		if dl[i].Index != want.idx || dl[i].Iteration != want.iter || !dl[i].Due.Equal(due) {
+			t.Errorf("dead letter %d: got %+v, want index %d of iteration %d", i, dl[i], want.idx, want.iter)
+		}
+	}
+	g.ClearDeadLetters()
+	if len(g.DeadLetters()) != 0 {
+		t.Error("expected no dead letters after clearing")
+	}
+}
+
+func TestMissReplay(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 0}, {Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2, OnMiss: schedule.MissReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	type result struct {
+		v          int
+		ok         bool
+		next       time.Duration
+		backfilled bool
+	}
+	now := start.Add(4500 * time.Millisecond) // First call after a gap.
+	var got []result
+	for i := 0; i < 5; i++ {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, result{v, ok, next, g.Backfilled()})
+	}
+	now = start.Add(7 * time.Second) // Last action missed before group ends.
+	for i := 0; i < 2; i++ {
+		v, ok, next, err := g.ScheduleNext(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, result{v, ok, next, g.Backfilled()})
+	}
+	if id, ok := g.LastTrigger(); !ok || id != (schedule.TriggerID{Iteration: 1, Index: 2, Seq: 5}) {
+		t.Errorf("got trigger %v, want 1:2#5", id)
+	}
+	want := []result{
+		{0, true, 0, true}, {1, true, 0, true}, {2, true, 0, true}, {0, true, 0, true},
+		{1, true, 500 * time.Millisecond, false},
+		{2, true, 0, true},
+		{0, false, 0, true}, // Done.
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 0}, {Duration: 0, Value: 1}, {Duration: 2 * time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Watermark().IsZero() {
+		t.Error("expected zero watermark before Begin")
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for _, test := range []struct {
+		now  time.Duration
+		want time.Duration
+	}{
+		{-time.Second, 0}, // Not started yet.
+		{0, time.Second},
+		{1500 * time.Millisecond, time.Second}, // Zero duration action delivered first.
+		{1500 * time.Millisecond, 3 * time.Second},
+		{3 * time.Second, 4 * time.Second},
+		{4 * time.Second, 4 * time.Second},
+		{4 * time.Second, 6 * time.Second}, // All actions delivered.
+		{6 * time.Second, 6 * time.Second},
+	} {
+		g.ScheduleNext(start.Add(test.now))
+		if got := g.Watermark(); !got.Equal(start.Add(test.want)) {
+			t.Errorf("at %s: got watermark %s, want %s", test.now, got.Sub(start), test.want)
+		}
+	}
+}
+
+func TestMissWarn(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1, OnMiss: schedule.MissWarn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	v, ok, next, err := g.ScheduleNext(start.Add(3500 * time.Millisecond))
+	var warn *schedule.MissWarning
+	if !errors.As(err, &warn) || warn.Missed != 2 || warn.Index != 1 || warn.Iteration != 0 {
+		t.Fatalf("got error %v, want warning of 2 missed actions starting at action 1", err)
+	}
+	if !ok || v != 1 || next != 500*time.Millisecond {
+		t.Errorf("got (%d, %t, %s) alongside warning, want (1, true, 500ms)", v, ok, next)
+	}
+	// Miss is reported once and the group continues.
+	_, ok, _, err = g.ScheduleNext(start.Add(3600 * time.Millisecond))
+	if err != nil || ok {
+		t.Errorf("got (%t, %v) after warning, want waiting without error", ok, err)
+	}
+	v, ok, _, err = g.ScheduleNext(start.Add(4 * time.Second))
+	if err != nil || !ok || v != 2 {
+		t.Errorf("got (%d, %t, %v), want (2, true, nil)", v, ok, err)
+	}
+}
+
+func TestScheduleNextNext(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: 0, Value: 2}, {Duration: 2 * time.Second, Value: 3}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	if _, _, next, err := g.ScheduleNext(start); err == nil || next != 0 {
+		t.Errorf("before Begin: got next=%s err=%v, want zero next with error", next, err)
+	}
+	g.Begin(start)
+	for _, test := range []struct {
+		desc string
+		now  time.Duration
+		ok   bool
+		next time.Duration
+	}{
+		{"before start", -1500 * time.Millisecond, false, 1500 * time.Millisecond},
+		{"first action", 0, true, time.Second},
+		{"mid action", 250 * time.Millisecond, false, 750 * time.Millisecond},
+		{"zero duration action", time.Second, true, 0},
+		{"action after zero duration", time.Second, true, 2 * time.Second},
+		{"last action of iteration", 2500 * time.Millisecond, false, 500 * time.Millisecond},
+	} {
+		_, ok, next, err := g.ScheduleNext(start.Add(test.now))
+		if err != nil || ok != test.ok || next != test.next {
+			t.Errorf("%s: got ok=%t next=%s err=%v, want ok=%t next=%s", test.desc, ok, next, err, test.ok, test.next)
+		}
+	}
+	g.StopAt(start.Add(5 * time.Second))
+	if _, ok, next, err := g.ScheduleNext(start.Add(3 * time.Second)); err != nil || !ok || next != time.Second {
+		t.Errorf("action capped by stop: got ok=%t next=%s err=%v, want ok with next=1s", ok, next, err)
+	}
+	if _, ok, next, err := g.ScheduleNext(start.Add(5 * time.Second)); err != nil || ok || next != 0 {
+		t.Errorf("stopped with iterations remaining: got ok=%t next=%s err=%v, want done", ok, next, err)
+	}
+	g.Begin(start)
+	g.ScheduleNext(start)
+	if _, _, next, err := g.ScheduleNext(start.Add(3500 * time.Millisecond)); err == nil || next != 0 {
+		t.Errorf("missed action: got next=%s err=%v, want zero next with error", next, err)
+	}
+	if _, _, next, err := g.ScheduleNext(start.Add(4 * time.Second)); err == nil || next != 0 {
+		t.Errorf("failed: got next=%s err=%v, want zero next with error", next, err)
+	}
+}
+
+func TestCoarseResolution(t *testing.T) {
+	const day = 24 * time.Hour
+	actions := []actionInt{{Duration: 30 * day, Value: 1}, {Duration: 60 * day, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 200, Resolution: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g.Begin(start)
+	if _, ok, _, err := g.ScheduleNext(start.Add(20 * time.Second)); err != nil || !ok {
+		t.Fatalf("got ok=%t err=%v, want first action", ok, err)
+	}
+	// Jitter below resolution does not make the second action due early.
+	due := start.Add(30 * day)
+	_, ok, next, err := g.ScheduleNext(due.Add(-time.Second))
+	if err != nil || ok || next != time.Second {
+		t.Errorf("got ok=%t next=%s err=%v a second before the second action, want waiting 1s", ok, next, err)
+	}
+	v, ok, next, err := g.ScheduleNext(due.Add(30 * time.Second))
+	if err != nil || !ok || v != 2 || next != 60*day-30*time.Second {
+		t.Errorf("got (%d, %t, %s, %v), want second action with next measured from now", v, ok, next, err)
+	}
+	// Long horizon: the final iteration ends roughly 49 years after start.
+	end := start.Add(200 * 90 * day)
+	if got := g.IterationBoundary(200); !got.Equal(end) {
+		t.Errorf("got end %s, want %s", got, end)
+	}
+	if _, err = schedule.NewGroupSync([]actionInt{{Duration: 90 * time.Second}}, schedule.GroupSyncConfig{Iterations: 1, Resolution: time.Minute}); err == nil {
+		t.Error("expected error for duration not multiple of resolution")
+	}
+
+	// Off-grid stop times reached within the lag are not reported as negative waits.
+	rec := schedule.NewRecorder(2)
+	g, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Resolution: time.Minute, Recorder: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.StopAt(start.Add(90 * time.Second))
+	var nexts []time.Duration
+	for _, elapsed := range []time.Duration{20 * time.Second, 100 * time.Second} {
+		_, _, next, err = g.ScheduleNext(start.Add(elapsed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nexts = append(nexts, next)
+	}
+	if nexts[0] != 70*time.Second || nexts[1] != 0 {
+		t.Errorf("got nexts %v, want [1m10s 0s]", nexts)
+	}
+	for i, d := range rec.Decisions() {
+		if d.Next != nexts[i] {
+			t.Errorf("decision %d recorded next %s, want returned %s", i, d.Next, nexts[i])
+		}
+	}
+}
+
+func TestCompleteLate(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, CompleteLate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	// Second action is triggered 300ms late and still runs for a full second.
+	v, ok, next, err := g.ScheduleNext(start.Add(1300 * time.Millisecond))
+	if err != nil || !ok || v != 2 || next != time.Second {
+		t.Fatalf("got (%d, %t, %s, %v), want second action running for 1s", v, ok, next, err)
+	}
+	_, ok, next, _ = g.ScheduleNext(start.Add(2100 * time.Millisecond))
+	if ok || next != 200*time.Millisecond {
+		t.Errorf("got ok=%t next=%s, want third action delayed by lateness", ok, next)
+	}
+	if v, ok, _, _ = g.ScheduleNext(start.Add(2300 * time.Millisecond)); !ok || v != 3 {
+		t.Errorf("got (%d, %t), want third action", v, ok)
+	}
+	// Misses are still detected.
+	g.Begin(start)
+	g.ScheduleNext(start)
+	if _, _, _, err = g.ScheduleNext(start.Add(2500 * time.Millisecond)); err == nil {
+		t.Error("expected missed action error")
+	}
+}
+
+func TestLazyBegin(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	gs, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gl, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := time.Unix(100, 0)
+	for _, g := range []GroupInt{gs, gl} {
+		g.Begin(time.Time{})
+		v, ok, next, err := g.ScheduleNext(first)
+		if err != nil || !ok || v != 1 || next != time.Second {
+			t.Errorf("%T: got (%d, %t, %s, %v), want full first action", g, v, ok, next, err)
+		}
+		if !g.StartTime().Equal(first) {
+			t.Errorf("%T: got start %s, want time of first call", g, g.StartTime())
+		}
+	}
+
+	// State set up between a lazy Begin and the first call is kept.
+	gs.Begin(time.Time{})
+	gs.StopAt(first.Add(1500 * time.Millisecond))
+	gs.Inject(7, first.Add(500*time.Millisecond))
+	gl.Begin(time.Time{})
+	gl.StopAfter(1500 * time.Millisecond)
+	for _, g := range []GroupInt{gs, gl} {
+		var got []int
+		for _, poll := range []time.Duration{0, 500, 1000, 1500} {
+			v, ok, next, err := g.ScheduleNext(first.Add(poll * time.Millisecond))
+			if err != nil {
+				t.Fatalf("%T: %v", g, err)
+			}
+			if ok {
+				got = append(got, v)
+			} else if next == 0 && poll != 1500 {
+				t.Errorf("%T: done at %dms before stop time", g, poll)
+			} else if poll == 1500 && next != 0 {
+				t.Errorf("%T: not stopped at stop time", g)
+			}
+		}
+		want := []int{1, 7, 2}
+		if g == GroupInt(gl) {
+			want = []int{1, 2}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%T: got %v, want %v", g, got, want)
+		}
+	}
+}
+
+func TestGrouper(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	gs, _ := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	gl, _ := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1})
+	start := time.Unix(100, 0)
+	for _, g := range []schedule.Grouper[int]{gs, gl} {
+		g.Begin(start)
+		var sum int
+		for now := start; ; now = now.Add(time.Second) {
+			v, ok, next, err := g.ScheduleNext(now)
+			if err != nil {
+				t.Fatalf("%T: %v", g, err)
+			}
+			if !ok && next == 0 {
+				break
+			}
+			sum += v
+		}
+		if sum != 3 || g.Duration() != 2*time.Second || g.Iterations() != 1 || !g.StartTime().Equal(start) {
+			t.Errorf("%T: got sum=%d duration=%s iterations=%d", g, sum, g.Duration(), g.Iterations())
+		}
+	}
+}
+
+func TestCurrentIteration(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for i, want := range []int{0, 0, 1, 1, 2, 2, 3, 3} {
+		g.ScheduleNext(start.Add(time.Duration(i) * time.Second))
+		if got := g.CurrentIteration(); got != want {
+			t.Errorf("at %ds got iteration %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestScheduleNextBeforeBegin(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}}
+	gs, _ := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	gl, _ := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1})
+	gn, _ := schedule.NewGroupNested([]schedule.Phase[int]{{Inner: actions, Repeat: 1}}, schedule.GroupNestedConfig{Iterations: 1})
+	gsel, _ := schedule.NewGroupSelect(actions, actions, func(int) bool { return false }, schedule.GroupSyncConfig{Iterations: 1})
+	for _, g := range []schedule.Grouper[int]{gs, gl, gn, gsel} {
+		_, ok, next, err := g.ScheduleNext(time.Unix(100, 0))
+		if !errors.Is(err, schedule.ErrBeginNotCalled) || ok || next != 0 {
+			t.Errorf("%T: got ok=%t next=%s err=%v, want ErrBeginNotCalled", g, ok, next, err)
+		}
+	}
+}
+
+func TestMustGroup(t *testing.T) {
+	if g := schedule.MustGroupSync([]actionInt{{Duration: 1, Value: 1}}, schedule.GroupSyncConfig{Iterations: 1}); g == nil {
+		t.Error("small duration should not be considered invalid")
+	}
+	if g := schedule.MustGroupLoose([]actionInt{{Value: 1}}, schedule.GroupLooseConfig{Iterations: 1}); g == nil {
+		t.Error("got nil loose group")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid group")
+		}
+	}()
+	schedule.MustGroupSync([]actionInt{}, schedule.GroupSyncConfig{Iterations: 1})
+}
+
+func TestMissedActionErrors(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	gs, _ := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	gl, _ := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 1, MaxLate: time.Second / 2})
+	gn, _ := schedule.NewGroupNested([]schedule.Phase[int]{{Inner: actions, Repeat: 1}}, schedule.GroupNestedConfig{Iterations: 1})
+	gsel, _ := schedule.NewGroupSelect(actions, actions, func(int) bool { return false }, schedule.GroupSyncConfig{Iterations: 1})
+	start := time.Unix(100, 0)
+	for _, g := range []schedule.Grouper[int]{gs, gl, gn, gsel} {
+		g.Begin(start)
+		g.ScheduleNext(start)
+		_, _, _, err := g.ScheduleNext(start.Add(2500 * time.Millisecond))
+		if !errors.Is(err, schedule.ErrMissedAction) {
+			t.Errorf("%T: got %v, want ErrMissedAction", g, err)
+		}
+		var actionErr *schedule.ActionError
+		if !errors.As(err, &actionErr) || actionErr.Index != 1 || actionErr.Iteration != 0 {
+			t.Errorf("%T: got %v, want error locating missed action", g, err)
+		}
+		_, _, _, err = g.ScheduleNext(start.Add(2600 * time.Millisecond))
+		if !errors.Is(err, schedule.ErrGroupFailed) {
+			t.Errorf("%T: got %v, want ErrGroupFailed", g, err)
+		}
+	}
+}
+
+func TestActionName(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1, Name: "fill"}, {Duration: time.Second, Value: 2, Name: "drain"}}
+	if got := actions[1].String(); got != "drain(2) for 1s" {
+		t.Errorf("got %q", got)
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	_, _, _, err = g.ScheduleNext(start.Add(2 * time.Second))
+	var actionErr *schedule.ActionError
+	if !errors.As(err, &actionErr) || actionErr.Name != "drain" || actionErr.Index != 1 || actionErr.Iteration != 0 {
+		t.Fatalf("got %v, want error naming missed action", err)
+	}
+	if !errors.Is(err, schedule.ErrMissedAction) {
+		t.Error("action error does not wrap ErrMissedAction")
+	}
+}
+
+func TestTolerance(t *testing.T) {
+	actions := []actionInt{
+		{Duration: time.Second, Value: 1},
+		{Duration: 100 * time.Millisecond, Value: 2, Tolerance: 300 * time.Millisecond},
+		{Duration: time.Second, Value: 3},
+	}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.RequiredResolution(); got != 300*time.Millisecond {
+		t.Errorf("got required resolution %s, want tolerance of shortest action", got)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	g.ScheduleNext(start)
+	// Poll arrives after the short action ended but within its tolerance.
+	ev, err := g.ScheduleNextEvent(start.Add(1250 * time.Millisecond))
+	if err != nil || !ev.Ok || ev.Value != 2 || ev.Next != 0 || ev.Lateness != 250*time.Millisecond {
+		t.Fatalf("got event %+v, err %v, want tolerated action", ev, err)
+	}
+	v, ok, _, err := g.ScheduleNext(start.Add(1250 * time.Millisecond))
+	if err != nil || !ok || v != 3 {
+		t.Errorf("got (%d, %t, %v), want action following tolerated action", v, ok, err)
+	}
+	// Past tolerance the group fails.
+	g.Begin(start)
+	g.ScheduleNext(start)
+	if _, _, _, err = g.ScheduleNext(start.Add(1500 * time.Millisecond)); !errors.Is(err, schedule.ErrMissedAction) {
+		t.Errorf("got %v, want missed action past tolerance", err)
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}, {Duration: time.Second, Value: 4}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, OnMiss: schedule.MissReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	got, next, err := g.ScheduleDue(nil, start.Add(2500*time.Millisecond))
+	if err != nil || !slices.Equal(got, []int{1, 2, 3}) || next != 500*time.Millisecond {
+		t.Errorf("got %v next=%s err=%v, want burst of missed actions", got, next, err)
+	}
+	got, next, err = g.ScheduleDue(got[:0], start.Add(2700*time.Millisecond))
+	if err != nil || len(got) != 0 || next != 300*time.Millisecond {
+		t.Errorf("got %v next=%s err=%v while waiting", got, next, err)
+	}
+	got, _, _ = g.ScheduleDue(got[:0], start.Add(5*time.Second))
+	if !slices.Equal(got, []int{4}) {
+		t.Errorf("got %v, want last action replayed after group end", got)
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: -1, OnMiss: schedule.MissSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(100, 0)
+	g.Begin(start)
+	for _, poll := range []time.Duration{0, 2, 3, 7, 8} {
+		if _, _, _, err := g.ScheduleNext(start.Add(poll * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if g.Skipped() != 4 {
+		t.Errorf("got %d skipped actions, want 4", g.Skipped())
+	}
+	g.Begin(start)
+	if g.Skipped() != 0 {
+		t.Error("Begin did not reset skipped actions")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}, {Duration: time.Second, Value: 3}}
+	pressureOK := false
+	guard := func(index int) bool { return index != 1 || pressureOK }
+	start := time.Unix(100, 0)
+
+	g, err := schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Guard: guard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNext(start)
+	_, ok, next, err := g.ScheduleNext(start.Add(1100 * time.Millisecond))
+	if err != nil || ok || next != 900*time.Millisecond {
+		t.Errorf("got ok=%t next=%s err=%v, want skipped action", ok, next, err)
+	}
+	if v, ok, _, _ := g.ScheduleNext(start.Add(2 * time.Second)); !ok || v != 3 {
+		t.Errorf("got (%d, %t), want action following skipped action", v, ok)
+	}
+
+	g, err = schedule.NewGroupSync(actions, schedule.GroupSyncConfig{Iterations: 1, Guard: guard, OnGuard: schedule.GuardHold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Begin(start)
+	g.ScheduleNext(start)
+	for _, poll := range []time.Duration{1100, 1900, 2500} {
+		if _, ok, _, err := g.ScheduleNext(start.Add(poll * time.Millisecond)); ok || err != nil {
+			t.Fatalf("at %dms got ok=%t err=%v, want held action", poll, ok, err)
+		}
+	}
+	pressureOK = true
+	v, ok, next, err := g.ScheduleNext(start.Add(2600 * time.Millisecond))
+	if err != nil || !ok || v != 2 || next != time.Second {
+		t.Errorf("got (%d, %t, %s, %v), want held action with full duration", v, ok, next, err)
+	}
+	if v, ok, _, _ := g.ScheduleNext(start.Add(3600 * time.Millisecond)); !ok || v != 3 {
+		t.Errorf("got (%d, %t), want delayed action following held action", v, ok)
+	}
+}
+
+func TestLooseJitter(t *testing.T) {
+	actions := []actionInt{{Duration: time.Second, Value: 1}, {Duration: time.Second, Value: 2}}
+	g, err := schedule.NewGroupLoose(actions, schedule.GroupLooseConfig{Iterations: 50, Jitter: 100 * time.Millisecond, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func() (nexts []time.Duration) {
+		start := time.Unix(100, 0)
+		g.Begin(start)
+		for now := start; ; {
+			_, ok, next, err := g.ScheduleNext(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok && next == 0 {
+				return nexts
+			}
+			if ok {
+				nexts = append(nexts, next)
+			}
+			now = now.Add(next)
+		}
+	}
+	nexts := run()
+	distinct := make(map[time.Duration]bool)
+	for _, next := range nexts {
+		if next < 900*time.Millisecond || next > 1100*time.Millisecond {
+			t.Fatalf("got duration %s outside jitter bounds", next)
+		}
+		distinct[next] = true
+	}
+	if len(distinct) < len(nexts)/2 {
+		t.Errorf("got %d distinct durations out of %d, want jittered durations", len(distinct), len(nexts))
+	}
+	if again := run(); !slices.Equal(nexts, again) {
+		t.Error("jitter not reproducible after Begin")
+	}
+}